@@ -0,0 +1,112 @@
+package ovr
+
+// A backend implements the global part of the C-API: initialization, device
+// discovery, timing and the stateless math functions. Which backend is used
+// is decided at build time; see newBackend() in the backend_*.go files.
+type backend interface {
+	initializeRenderingShim() error
+	initialize() error
+	shutdown()
+	unloadLibrary() error
+	versionString() string
+	detect() int
+	create(index int) *Hmd
+	createDebug(hmdType HmdType) *Hmd
+	devices() []DeviceInfo
+
+	renderScaleAndOffset(fov FovPort, textureSize Sizei, renderViewport Recti) [2]Vector2f
+	projection(fov FovPort, znear float32, zfar float32, rightHanded bool) Matrix4f
+	orthoSubProjection(projection Matrix4f, orthoScale Vector2f, orthoDistance float32, eyeViewAdjustX float32) Matrix4f
+	timeInSeconds() float64
+	waitTillTime(absTime float64) float64
+}
+
+// A device implements the per-HMD part of the C-API. Every Hmd holds one and
+// dispatches its methods to it.
+type device interface {
+	destroy()
+	lastError() *string
+	enabledCaps() uint
+	setEnabledCaps(hmdCaps uint)
+
+	configureTracking(supportedTrackingCaps uint, requiredTrackingCaps uint) bool
+	recenterPose()
+	trackingState(absTime float64) TrackingState
+
+	fovTextureSize(eye EyeType, fov FovPort, pixelsPerDisplayPixel float32) Sizei
+
+	configureRendering(apiConfig *RenderAPIConfig, distortionCaps uint, eyeFovIn [2]FovPort) ([2]EyeRenderDesc, bool)
+	beginFrame(frameIndex uint) FrameTiming
+	endFrame(renderPose [2]Posef, eyeTexture [2]Texture)
+	eyePose(eye EyeType) Posef
+
+	renderDesc(eye EyeType, fov FovPort) EyeRenderDesc
+	createDistortionMesh(eye EyeType, fov FovPort, distortionCaps uint) (*DistortionMesh, bool)
+	frameTiming(frameIndex uint) FrameTiming
+	beginFrameTiming(frameIndex uint) FrameTiming
+	endFrameTiming()
+	resetFrameTiming(frameIndex uint)
+	eyeTimewarpMatrices(eye EyeType, renderPose Posef) [2]Matrix4f
+
+	processLatencyTest() (*[3]uint, bool)
+	latencyTestResult() *string
+
+	hswDisplayState() HSWDisplayState
+	dismissHSWDisplay() bool
+
+	getBool(propertyName string, defaultVal bool) bool
+	setBool(propertyName string, value bool) bool
+	getInt(propertyName string, defaultVal int) int
+	setInt(propertyName string, value int) bool
+	getFloat(propertyName string, defaultVal float32) float32
+	setFloat(propertyName string, value float32) bool
+	getFloatArray(propertyName string, values []float32, arraySize uint) uint
+	setFloatArray(propertyName string, values []float32, arraySize uint) bool
+	getString(propertyName, defaultVal string) string
+	setString(propertyName, value string) bool
+}
+
+// A deviceWrapper is a device that adds behavior to another device, like the
+// recorder does.
+type deviceWrapper interface {
+	unwrap() device
+
+	// rewrap makes the wrapper pass its calls on to dev instead.
+	rewrap(dev device)
+}
+
+// Returns the device at the bottom of any wrappers.
+func baseDevice(dev device) device {
+	for {
+		wrapper, ok := dev.(deviceWrapper)
+		if !ok {
+			return dev
+		}
+
+		dev = wrapper.unwrap()
+	}
+}
+
+// Removes the wrapper layer from the devices of hmd, and leaves the wrappers
+// around it in place. Returns false if layer doesn't wrap the device of hmd.
+func removeWrapper(hmd *Hmd, layer device) bool {
+	if hmd.dev == layer {
+		hmd.dev = layer.(deviceWrapper).unwrap()
+		return true
+	}
+
+	dev := hmd.dev
+	for {
+		wrapper, ok := dev.(deviceWrapper)
+		if !ok {
+			return false
+		}
+
+		if dev = wrapper.unwrap(); dev == layer {
+			wrapper.rewrap(layer.(deviceWrapper).unwrap())
+			return true
+		}
+	}
+}
+
+var currentBackend = newBackend()
@@ -0,0 +1,971 @@
+//go:build libovr
+
+package ovr
+
+/*
+#cgo darwin CFLAGS: -DOVR_OS_MAC
+#cgo linux LDFLAGS: -ldl
+#cgo windows CFLAGS: -DOVR_OS_WIN32 -I C:/libovr_0.4/dynamic
+#include <stdlib.h>
+#include <string.h>
+#include "libovr_loader.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+func ovrBool(b bool) C.ovrBool {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func newBool(b C.ovrBool) bool {
+	if b == 1 {
+		return true
+	}
+	return false
+}
+
+// The constants in ovr.go are plain Go values, so they can be used without
+// cgo. A constant that differs from the one of the SDK makes one of these
+// differences negative, which doesn't fit a uint, and fails the build.
+const (
+	_ = uint(Hmd_None - C.ovrHmd_None)
+	_ = uint(C.ovrHmd_None - Hmd_None)
+	_ = uint(Hmd_DK1 - C.ovrHmd_DK1)
+	_ = uint(C.ovrHmd_DK1 - Hmd_DK1)
+	_ = uint(Hmd_DKHD - C.ovrHmd_DKHD)
+	_ = uint(C.ovrHmd_DKHD - Hmd_DKHD)
+	_ = uint(Hmd_DK2 - C.ovrHmd_DK2)
+	_ = uint(C.ovrHmd_DK2 - Hmd_DK2)
+	_ = uint(Hmd_Other - C.ovrHmd_Other)
+	_ = uint(C.ovrHmd_Other - Hmd_Other)
+
+	_ = uint(HmdCap_Present - C.ovrHmdCap_Present)
+	_ = uint(C.ovrHmdCap_Present - HmdCap_Present)
+	_ = uint(HmdCap_Available - C.ovrHmdCap_Available)
+	_ = uint(C.ovrHmdCap_Available - HmdCap_Available)
+	_ = uint(HmdCap_Captured - C.ovrHmdCap_Captured)
+	_ = uint(C.ovrHmdCap_Captured - HmdCap_Captured)
+	_ = uint(HmdCap_ExtendDesktop - C.ovrHmdCap_ExtendDesktop)
+	_ = uint(C.ovrHmdCap_ExtendDesktop - HmdCap_ExtendDesktop)
+	_ = uint(HmdCap_NoMirrorToWindow - C.ovrHmdCap_NoMirrorToWindow)
+	_ = uint(C.ovrHmdCap_NoMirrorToWindow - HmdCap_NoMirrorToWindow)
+	_ = uint(HmdCap_DisplayOff - C.ovrHmdCap_DisplayOff)
+	_ = uint(C.ovrHmdCap_DisplayOff - HmdCap_DisplayOff)
+	_ = uint(HmdCap_LowPersistence - C.ovrHmdCap_LowPersistence)
+	_ = uint(C.ovrHmdCap_LowPersistence - HmdCap_LowPersistence)
+	_ = uint(HmdCap_DynamicPrediction - C.ovrHmdCap_DynamicPrediction)
+	_ = uint(C.ovrHmdCap_DynamicPrediction - HmdCap_DynamicPrediction)
+	_ = uint(HmdCap_NoVSync - C.ovrHmdCap_NoVSync)
+	_ = uint(C.ovrHmdCap_NoVSync - HmdCap_NoVSync)
+	_ = uint(HmdCap_Writable_Mask - C.ovrHmdCap_Writable_Mask)
+	_ = uint(C.ovrHmdCap_Writable_Mask - HmdCap_Writable_Mask)
+	_ = uint(HmdCap_Service_Mask - C.ovrHmdCap_Service_Mask)
+	_ = uint(C.ovrHmdCap_Service_Mask - HmdCap_Service_Mask)
+
+	_ = uint(TrackingCap_Orientation - C.ovrTrackingCap_Orientation)
+	_ = uint(C.ovrTrackingCap_Orientation - TrackingCap_Orientation)
+	_ = uint(TrackingCap_MagYawCorrection - C.ovrTrackingCap_MagYawCorrection)
+	_ = uint(C.ovrTrackingCap_MagYawCorrection - TrackingCap_MagYawCorrection)
+	_ = uint(TrackingCap_Position - C.ovrTrackingCap_Position)
+	_ = uint(C.ovrTrackingCap_Position - TrackingCap_Position)
+	_ = uint(TrackingCap_Idle - C.ovrTrackingCap_Idle)
+	_ = uint(C.ovrTrackingCap_Idle - TrackingCap_Idle)
+
+	_ = uint(DistortionCap_Chromatic - C.ovrDistortionCap_Chromatic)
+	_ = uint(C.ovrDistortionCap_Chromatic - DistortionCap_Chromatic)
+	_ = uint(DistortionCap_TimeWarp - C.ovrDistortionCap_TimeWarp)
+	_ = uint(C.ovrDistortionCap_TimeWarp - DistortionCap_TimeWarp)
+	_ = uint(DistortionCap_Vignette - C.ovrDistortionCap_Vignette)
+	_ = uint(C.ovrDistortionCap_Vignette - DistortionCap_Vignette)
+	_ = uint(DistortionCap_NoRestore - C.ovrDistortionCap_NoRestore)
+	_ = uint(C.ovrDistortionCap_NoRestore - DistortionCap_NoRestore)
+	_ = uint(DistortionCap_FlipInput - C.ovrDistortionCap_FlipInput)
+	_ = uint(C.ovrDistortionCap_FlipInput - DistortionCap_FlipInput)
+	_ = uint(DistortionCap_SRGB - C.ovrDistortionCap_SRGB)
+	_ = uint(C.ovrDistortionCap_SRGB - DistortionCap_SRGB)
+	_ = uint(DistortionCap_Overdrive - C.ovrDistortionCap_Overdrive)
+	_ = uint(C.ovrDistortionCap_Overdrive - DistortionCap_Overdrive)
+	_ = uint(DistortionCap_ProfileNoTimewarpSpinWaits - C.ovrDistortionCap_ProfileNoTimewarpSpinWaits)
+	_ = uint(C.ovrDistortionCap_ProfileNoTimewarpSpinWaits - DistortionCap_ProfileNoTimewarpSpinWaits)
+
+	_ = uint(Status_OrientationTracked - C.ovrStatus_OrientationTracked)
+	_ = uint(C.ovrStatus_OrientationTracked - Status_OrientationTracked)
+	_ = uint(Status_PositionTracked - C.ovrStatus_PositionTracked)
+	_ = uint(C.ovrStatus_PositionTracked - Status_PositionTracked)
+	_ = uint(Status_CameraPoseTracked - C.ovrStatus_CameraPoseTracked)
+	_ = uint(C.ovrStatus_CameraPoseTracked - Status_CameraPoseTracked)
+	_ = uint(Status_PositionConnected - C.ovrStatus_PositionConnected)
+	_ = uint(C.ovrStatus_PositionConnected - Status_PositionConnected)
+	_ = uint(Status_HmdConnected - C.ovrStatus_HmdConnected)
+	_ = uint(C.ovrStatus_HmdConnected - Status_HmdConnected)
+)
+
+// ****************************************************************************
+// ************************ [ Simple Math Structures ] ************************
+// ****************************************************************************
+
+func (vector Vector2i) toC() C.ovrVector2i {
+	return C.ovrVector2i{x: C.int(vector.X), y: C.int(vector.Y)}
+}
+
+func newVector2i(vector C.ovrVector2i) Vector2i {
+	return Vector2i{X: int(vector.x), Y: int(vector.y)}
+}
+
+func (size Sizei) toC() C.ovrSizei {
+	return C.ovrSizei{w: C.int(size.W), h: C.int(size.H)}
+}
+
+func newSizei(size C.ovrSizei) Sizei {
+	return Sizei{W: int(size.w), H: int(size.h)}
+}
+
+func (rect Recti) toC() C.ovrRecti {
+	return C.ovrRecti{Pos: rect.Pos.toC(), Size: rect.Size.toC()}
+}
+
+func newRecti(rect C.ovrRecti) Recti {
+	return Recti{Pos: newVector2i(rect.Pos), Size: newSizei(rect.Size)}
+}
+
+func (quat Quatf) toC() C.ovrQuatf {
+	return C.ovrQuatf{x: C.float(quat.X), y: C.float(quat.Y), z: C.float(quat.Z), w: C.float(quat.W)}
+}
+
+func newQuatf(quat C.ovrQuatf) Quatf {
+	return Quatf{X: float32(quat.x), Y: float32(quat.y), Z: float32(quat.z), W: float32(quat.w)}
+}
+
+func (vector Vector2f) toC() C.ovrVector2f {
+	return C.ovrVector2f{x: C.float(vector.X), y: C.float(vector.Y)}
+}
+
+func newVector2f(vector C.ovrVector2f) Vector2f {
+	return Vector2f{X: float32(vector.x), Y: float32(vector.y)}
+}
+
+func (vector Vector3f) toC() C.ovrVector3f {
+	return C.ovrVector3f{x: C.float(vector.X), y: C.float(vector.Y), z: C.float(vector.Z)}
+}
+
+func newVector3f(vector C.ovrVector3f) Vector3f {
+	return Vector3f{X: float32(vector.x), Y: float32(vector.y), Z: float32(vector.z)}
+}
+
+func (matrix Matrix4f) toC() C.ovrMatrix4f {
+	M := [4][4]C.float{}
+
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			M[x][y] = C.float(matrix.M[x][y])
+		}
+	}
+
+	return C.ovrMatrix4f{M: M}
+}
+
+func newMatrix4f(matrix C.ovrMatrix4f) Matrix4f {
+	m := Matrix4f{}
+
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			m.M[x][y] = float32(matrix.M[x][y])
+		}
+	}
+
+	return m
+}
+
+func (posef Posef) toC() C.ovrPosef {
+	return C.ovrPosef{
+		Orientation: posef.Orientation.toC(),
+		Position:    posef.Position.toC(),
+	}
+}
+
+func newPosef(posef C.ovrPosef) Posef {
+	return Posef{
+		Orientation: newQuatf(posef.Orientation),
+		Position:    newVector3f(posef.Position),
+	}
+}
+
+func (poseState PoseStatef) toC() C.ovrPoseStatef {
+	return C.ovrPoseStatef{
+		ThePose:             poseState.ThePose.toC(),
+		AngularVelocity:     poseState.AngularVelocity.toC(),
+		LinearVelocity:      poseState.LinearVelocity.toC(),
+		AngularAcceleration: poseState.AngularAcceleration.toC(),
+		LinearAcceleration:  poseState.LinearAcceleration.toC(),
+		TimeInSeconds:       C.double(poseState.TimeInSeconds),
+	}
+}
+
+func newPoseStatef(poseStatef C.ovrPoseStatef) PoseStatef {
+	return PoseStatef{
+		ThePose:             newPosef(poseStatef.ThePose),
+		AngularVelocity:     newVector3f(poseStatef.AngularVelocity),
+		LinearVelocity:      newVector3f(poseStatef.LinearVelocity),
+		AngularAcceleration: newVector3f(poseStatef.AngularAcceleration),
+		LinearAcceleration:  newVector3f(poseStatef.LinearAcceleration),
+		TimeInSeconds:       float64(poseStatef.TimeInSeconds),
+	}
+}
+
+func (fov FovPort) toC() C.ovrFovPort {
+	return C.ovrFovPort{
+		UpTan:    C.float(fov.UpTan),
+		DownTan:  C.float(fov.DownTan),
+		LeftTan:  C.float(fov.LeftTan),
+		RightTan: C.float(fov.RightTan),
+	}
+}
+
+func newFovPort(fov C.ovrFovPort) FovPort {
+	return FovPort{
+		UpTan:    float32(fov.UpTan),
+		DownTan:  float32(fov.DownTan),
+		LeftTan:  float32(fov.LeftTan),
+		RightTan: float32(fov.RightTan),
+	}
+}
+
+// ****************************************************************************
+// ******************************* [ HMD types ] ******************************
+// ****************************************************************************
+
+func newHmd(hmd C.ovrHmd) *Hmd {
+	if hmd == nil {
+		return nil
+	}
+
+	return &Hmd{
+		dev:                        &libovrDevice{hmdRef: hmd, index: -1},
+		Type:                       HmdType(hmd.Type),
+		ProductName:                C.GoString(hmd.ProductName),
+		Manufacturer:               C.GoString(hmd.Manufacturer),
+		VendorId:                   int(hmd.VendorId),
+		ProductId:                  int(hmd.ProductId),
+		SerialNumber:               C.GoString(&hmd.SerialNumber[0]),
+		FirmwareMajor:              int(hmd.FirmwareMajor),
+		FirmwareMinor:              int(hmd.FirmwareMinor),
+		CameraFrustumHFovInRadians: float32(hmd.CameraFrustumHFovInRadians),
+		CameraFrustumVFovInRadians: float32(hmd.CameraFrustumVFovInRadians),
+		CameraFrustumNearZInMeters: float32(hmd.CameraFrustumNearZInMeters),
+		CameraFrustumFarZInMeters:  float32(hmd.CameraFrustumFarZInMeters),
+		HmdCaps:                    uint(hmd.HmdCaps),
+		TrackingCaps:               uint(hmd.TrackingCaps),
+		DistortionCaps:             uint(hmd.DistortionCaps),
+		DefaultEyeFov: [Eye_Count]FovPort{
+			newFovPort(hmd.DefaultEyeFov[0]),
+			newFovPort(hmd.DefaultEyeFov[1]),
+		},
+		MaxEyeFov: [Eye_Count]FovPort{
+			newFovPort(hmd.MaxEyeFov[0]),
+			newFovPort(hmd.MaxEyeFov[1]),
+		},
+		EyeRenderOrder: [Eye_Count]EyeType{
+			EyeType(hmd.EyeRenderOrder[0]),
+			EyeType(hmd.EyeRenderOrder[1]),
+		},
+		Resolution:        newSizei(hmd.Resolution),
+		WindowsPos:        newVector2i(hmd.WindowsPos),
+		DisplayDeviceName: C.GoString(hmd.DisplayDeviceName),
+		DisplayId:         int(hmd.DisplayId),
+	}
+}
+
+func (data SensorData) toC() C.ovrSensorData {
+	return C.ovrSensorData{
+		Accelerometer: data.Accelerometer.toC(),
+		Gyro:          data.Gyro.toC(),
+		Magnetometer:  data.Magnetometer.toC(),
+		Temperature:   C.float(data.Temperature),
+		TimeInSeconds: C.float(data.TimeInSeconds),
+	}
+}
+
+func newSensorData(data C.ovrSensorData) SensorData {
+	return SensorData{
+		Accelerometer: newVector3f(data.Accelerometer),
+		Gyro:          newVector3f(data.Gyro),
+		Magnetometer:  newVector3f(data.Magnetometer),
+		Temperature:   float32(data.Temperature),
+		TimeInSeconds: float32(data.TimeInSeconds),
+	}
+}
+
+func (state TrackingState) toC() C.ovrTrackingState {
+	return C.ovrTrackingState{
+		HeadPose:          state.HeadPose.toC(),
+		CameraPose:        state.CameraPose.toC(),
+		LeveledCameraPose: state.LeveledCameraPose.toC(),
+		RawSensorData:     state.RawSensorData.toC(),
+		StatusFlags:       C.uint(state.StatusFlags),
+	}
+}
+
+func newTrackingState(trackingState C.ovrTrackingState) TrackingState {
+	return TrackingState{
+		HeadPose:          newPoseStatef(trackingState.HeadPose),
+		CameraPose:        newPosef(trackingState.CameraPose),
+		LeveledCameraPose: newPosef(trackingState.LeveledCameraPose),
+		RawSensorData:     newSensorData(trackingState.RawSensorData),
+		StatusFlags:       uint(trackingState.StatusFlags),
+	}
+}
+
+func newFrameTiming(timing C.ovrFrameTiming) FrameTiming {
+	return FrameTiming{
+		DeltaSeconds:           float32(timing.DeltaSeconds),
+		ThisFrameSeconds:       float64(timing.ThisFrameSeconds),
+		TimewarpPointSeconds:   float64(timing.TimewarpPointSeconds),
+		NextFrameSeconds:       float64(timing.NextFrameSeconds),
+		ScanoutMidpointSeconds: float64(timing.ScanoutMidpointSeconds),
+		EyeScanoutSeconds: [2]float64{
+			float64(timing.EyeScanoutSeconds[0]),
+			float64(timing.EyeScanoutSeconds[1]),
+		},
+	}
+}
+
+func (desc EyeRenderDesc) toC() C.ovrEyeRenderDesc {
+	return C.ovrEyeRenderDesc{
+		Eye:                       C.ovrEyeType(desc.Eye),
+		Fov:                       desc.Fov.toC(),
+		DistortedViewport:         desc.DistortedViewport.toC(),
+		PixelsPerTanAngleAtCenter: desc.PixelsPerTanAngleAtCenter.toC(),
+		ViewAdjust:                desc.ViewAdjust.toC(),
+	}
+}
+
+func newEyeRenderDesc(eyeRenderDesc C.ovrEyeRenderDesc) EyeRenderDesc {
+	return EyeRenderDesc{
+		Eye:                       EyeType(eyeRenderDesc.Eye),
+		Fov:                       newFovPort(eyeRenderDesc.Fov),
+		DistortedViewport:         newRecti(eyeRenderDesc.DistortedViewport),
+		PixelsPerTanAngleAtCenter: newVector2f(eyeRenderDesc.PixelsPerTanAngleAtCenter),
+		ViewAdjust:                newVector3f(eyeRenderDesc.ViewAdjust),
+	}
+}
+
+func (configHeader RenderAPIConfigHeader) toC() C.ovrRenderAPIConfigHeader {
+	return C.ovrRenderAPIConfigHeader{
+		API:         C.ovrRenderAPIType(configHeader.API),
+		RTSize:      configHeader.RTSize.toC(),
+		Multisample: C.int(configHeader.Multisample),
+	}
+}
+
+func (config RenderAPIConfig) toC() C.ovrRenderAPIConfig {
+	_config := C.ovrRenderAPIConfig{Header: config.Header.toC()}
+	for i, data := range config.PlatformData {
+		_config.PlatformData[i] = C.uintptr_t(data)
+	}
+
+	return _config
+}
+
+func (header TextureHeader) toC() C.ovrTextureHeader {
+	return C.ovrTextureHeader{
+		API:            C.ovrRenderAPIType(header.API),
+		TextureSize:    header.TextureSize.toC(),
+		RenderViewport: header.RenderViewport.toC(),
+	}
+}
+
+func newTextureHeader(header C.ovrTextureHeader) TextureHeader {
+	return TextureHeader{
+		API:            RenderAPIType(header.API),
+		TextureSize:    newSizei(header.TextureSize),
+		RenderViewport: newRecti(header.RenderViewport),
+	}
+}
+
+func (texture Texture) toC() C.ovrTexture {
+	_texture := C.ovrTexture{Header: texture.Header.toC()}
+	for i, data := range texture.PlatformData {
+		_texture.PlatformData[i] = C.uintptr_t(data)
+	}
+
+	return _texture
+}
+
+func newTexture(texture C.ovrTexture) Texture {
+	_texture := Texture{Header: newTextureHeader(texture.Header)}
+	for i, data := range texture.PlatformData {
+		_texture.PlatformData[i] = uintptr(data)
+	}
+
+	return _texture
+}
+
+func newDistortionVertex(vertex C.ovrDistortionVertex) DistortionVertex {
+	return DistortionVertex{
+		ScreenPosNDC:   newVector2f(vertex.ScreenPosNDC),
+		TimeWarpFactor: float32(vertex.TimeWarpFactor),
+		VignetteFactor: float32(vertex.VignetteFactor),
+		TanEyeAnglesR:  newVector2f(vertex.TanEyeAnglesR),
+		TanEyeAnglesG:  newVector2f(vertex.TanEyeAnglesG),
+		TanEyeAnglesB:  newVector2f(vertex.TanEyeAnglesB),
+	}
+}
+
+// Copy the mesh into Go memory, so the C allocation can be released right
+// away and the caller doesn't have to worry about it.
+func newDistortionMesh(mesh C.ovrDistortionMesh) *DistortionMesh {
+	vertexData := unsafe.Slice(mesh.pVertexData, mesh.VertexCount)
+	indexData := unsafe.Slice(mesh.pIndexData, mesh.IndexCount)
+
+	meshData := &DistortionMesh{
+		VertexData: make([]DistortionVertex, len(vertexData)),
+		IndexData:  make([]uint16, len(indexData)),
+	}
+
+	for i, vertex := range vertexData {
+		meshData.VertexData[i] = newDistortionVertex(vertex)
+	}
+
+	for i, index := range indexData {
+		meshData.IndexData[i] = uint16(index)
+	}
+
+	return meshData
+}
+
+func newHSWDisplayState(state C.ovrHSWDisplayState) HSWDisplayState {
+	return HSWDisplayState{
+		Displayed:       newBool(state.Displayed),
+		StartTime:       float64(state.StartTime),
+		DismissibleTime: float64(state.DismissibleTime),
+	}
+}
+
+// ****************************************************************************
+// ***************************** [ API interface ] ****************************
+// ****************************************************************************
+
+// The libovrBackend calls into the Oculus C-API through cgo. The library is
+// loaded at runtime by libovr_loader.c, so a program built with it still
+// starts on machines that don't have libOVR.
+type libovrBackend struct{}
+
+// The SDK can only describe a device once it has been created, so the devices
+// opened with HmdCreate() are kept by index for Devices() to describe. The SDK
+// lets a device be created more than once, so there can be several per index.
+var openDevices = struct {
+	sync.Mutex
+	hmds map[int][]*Hmd
+}{hmds: make(map[int][]*Hmd)}
+
+// The SDK reports the attached hardware, so AttachSimulatedHmd() can't add to
+// it.
+const simulatedHardware = false
+
+func newBackend() backend {
+	return libovrBackend{}
+}
+
+// Whether the library is loaded. Initialize(), InitializeRenderingShim() and
+// the functions that load the library on first use may run concurrently, so
+// loading and unloading are serialized.
+var library struct {
+	sync.Mutex
+	loaded bool
+
+	// The LibraryPath that last failed to load on first use, so it isn't
+	// tried again on every call.
+	failedPath *string
+}
+
+// Loads the library at LibraryPath, unless it is already loaded, and checks
+// that it is a 0.4.x release with every function the package uses.
+func loadLibrary() error {
+	library.Lock()
+	defer library.Unlock()
+
+	return loadLibraryLocked()
+}
+
+func loadLibraryLocked() error {
+	var path *C.char
+	if LibraryPath != "" {
+		path = C.CString(LibraryPath)
+		defer C.free(unsafe.Pointer(path))
+	}
+
+	if C.libovr_Load(path) == 0 {
+		return fmt.Errorf("failed to load libOVR from '%s': %s", LibraryPath, C.GoString(C.libovr_Error()))
+	}
+
+	if version := C.GoString(C.dl_ovr_GetVersionString()); !strings.HasPrefix(version, "libOVR:0.4.") {
+		C.libovr_Unload()
+		return fmt.Errorf("unsupported libOVR version '%s', a 0.4.x release is required", version)
+	}
+
+	library.loaded = true
+	library.failedPath = nil
+	return nil
+}
+
+// The functions that don't need Initialize() load the library on first use,
+// like the SDK linked in statically. They fall back to their pure Go versions
+// when it can't be loaded.
+func libraryReady() bool {
+	library.Lock()
+	defer library.Unlock()
+
+	if library.loaded {
+		return true
+	}
+
+	if library.failedPath != nil && *library.failedPath == LibraryPath {
+		return false
+	}
+
+	if err := loadLibraryLocked(); err != nil {
+		path := LibraryPath
+		library.failedPath = &path
+		return false
+	}
+
+	return true
+}
+
+func (libovrBackend) unloadLibrary() error {
+	openDevices.Lock()
+	open := len(openDevices.hmds) > 0
+	openDevices.Unlock()
+
+	if open {
+		return errors.New("libOVR can't be unloaded while devices are open")
+	}
+
+	library.Lock()
+	defer library.Unlock()
+
+	C.libovr_Unload()
+	library.loaded = false
+	library.failedPath = nil
+
+	return nil
+}
+
+func (libovrBackend) initializeRenderingShim() error {
+	if err := loadLibrary(); err != nil {
+		return err
+	}
+
+	if C.dl_ovr_InitializeRenderingShim() != 1 {
+		return errors.New("ovr_InitializeRenderingShim() failed")
+	}
+
+	return nil
+}
+
+func (libovrBackend) initialize() error {
+	if err := loadLibrary(); err != nil {
+		return err
+	}
+
+	if C.dl_ovr_Initialize() != 1 {
+		return errors.New("ovr_Initialize() failed")
+	}
+
+	return nil
+}
+
+func (libovrBackend) shutdown() {
+	C.dl_ovr_Shutdown()
+}
+
+// Without the library, there is no version to report.
+func (libovrBackend) versionString() string {
+	if !libraryReady() {
+		return ""
+	}
+
+	return C.GoString(C.dl_ovr_GetVersionString())
+}
+
+func (libovrBackend) detect() int {
+	if !libraryReady() {
+		return 0
+	}
+
+	return int(C.dl_ovrHmd_Detect())
+}
+
+func (libovrBackend) create(index int) *Hmd {
+	hmd := newHmd(C.dl_ovrHmd_Create(C.int(index)))
+	if hmd == nil {
+		return nil
+	}
+
+	openDevices.Lock()
+	defer openDevices.Unlock()
+
+	hmd.dev.(*libovrDevice).index = index
+	openDevices.hmds[index] = append(openDevices.hmds[index], hmd)
+
+	return hmd
+}
+
+func (libovrBackend) createDebug(hmdType HmdType) *Hmd {
+	return newHmd(C.dl_ovrHmd_CreateDebug(C.ovrHmdType(hmdType)))
+}
+
+// Creating a device to describe it would capture it, if only for a moment, so
+// only the devices that are already open are described.
+func (b libovrBackend) devices() []DeviceInfo {
+	n := b.detect()
+
+	openDevices.Lock()
+	defer openDevices.Unlock()
+
+	infos := []DeviceInfo{}
+	for index := 0; index < n; index++ {
+		if hmds := openDevices.hmds[index]; len(hmds) > 0 {
+			infos = append(infos, newDeviceInfo(index, hmds[0], true))
+		} else {
+			infos = append(infos, DeviceInfo{Index: index})
+		}
+	}
+
+	return infos
+}
+
+func (libovrBackend) renderScaleAndOffset(fov FovPort, textureSize Sizei, renderViewport Recti) [2]Vector2f {
+	if !libraryReady() {
+		return renderScaleAndOffset(fov, textureSize, renderViewport)
+	}
+
+	uvScaleOffsetOut := [2]C.ovrVector2f{}
+	C.dl_ovrHmd_GetRenderScaleAndOffset(fov.toC(), textureSize.toC(), renderViewport.toC(), &uvScaleOffsetOut[0])
+
+	return [2]Vector2f{newVector2f(uvScaleOffsetOut[0]), newVector2f(uvScaleOffsetOut[1])}
+}
+
+func (libovrBackend) projection(fov FovPort, znear float32, zfar float32, rightHanded bool) Matrix4f {
+	if !libraryReady() {
+		return projection(fov, znear, zfar, rightHanded)
+	}
+
+	return newMatrix4f(C.dl_ovrMatrix4f_Projection(fov.toC(), C.float(znear), C.float(zfar), ovrBool(rightHanded)))
+}
+
+func (libovrBackend) orthoSubProjection(projection Matrix4f, orthoScale Vector2f, orthoDistance float32, eyeViewAdjustX float32) Matrix4f {
+	if !libraryReady() {
+		return OrthoSubProjection(projection, orthoScale, orthoDistance, eyeViewAdjustX)
+	}
+
+	return newMatrix4f(C.dl_ovrMatrix4f_OrthoSubProjection(projection.toC(), orthoScale.toC(), C.float(orthoDistance), C.float(eyeViewAdjustX)))
+}
+
+func (libovrBackend) timeInSeconds() float64 {
+	if !libraryReady() {
+		return goTimeInSeconds()
+	}
+
+	return float64(C.dl_ovr_GetTimeInSeconds())
+}
+
+func (libovrBackend) waitTillTime(absTime float64) float64 {
+	if !libraryReady() {
+		return goWaitTillTime(absTime)
+	}
+
+	return float64(C.dl_ovr_WaitTillTime(C.double(absTime)))
+}
+
+// The libovrDevice wraps an ovrHmd handle. The index is the one it was
+// created with, or -1 for debug devices.
+type libovrDevice struct {
+	hmdRef C.ovrHmd
+	index  int
+}
+
+func (dev *libovrDevice) destroy() {
+	if dev.index >= 0 {
+		openDevices.Lock()
+		hmds := openDevices.hmds[dev.index]
+		for i, hmd := range hmds {
+			if baseDevice(hmd.dev) == dev {
+				hmds = append(hmds[:i], hmds[i+1:]...)
+				break
+			}
+		}
+
+		if len(hmds) == 0 {
+			delete(openDevices.hmds, dev.index)
+		} else {
+			openDevices.hmds[dev.index] = hmds
+		}
+		openDevices.Unlock()
+	}
+
+	C.dl_ovrHmd_Destroy(dev.hmdRef)
+}
+
+// The ovrHmd_GetLastError function has a bug, where it sends back an empty
+// string when there is no error, instead of NULL. Work around that.
+func (dev *libovrDevice) lastError() *string {
+	if str := C.dl_ovrHmd_GetLastError(dev.hmdRef); str != nil && C.strlen(str) != 0 {
+		goStr := C.GoString(str)
+		return &goStr
+	}
+
+	return nil
+}
+
+func (dev *libovrDevice) enabledCaps() uint {
+	return uint(C.dl_ovrHmd_GetEnabledCaps(dev.hmdRef))
+}
+
+func (dev *libovrDevice) setEnabledCaps(hmdCaps uint) {
+	C.dl_ovrHmd_SetEnabledCaps(dev.hmdRef, C.uint(hmdCaps))
+}
+
+// ****************************************************************************
+// ************************** [ Tracking interface ] **************************
+// ****************************************************************************
+
+func (dev *libovrDevice) configureTracking(supportedTrackingCaps uint, requiredTrackingCaps uint) bool {
+	return C.dl_ovrHmd_ConfigureTracking(dev.hmdRef, C.uint(supportedTrackingCaps), C.uint(requiredTrackingCaps)) == 1
+}
+
+func (dev *libovrDevice) recenterPose() {
+	C.dl_ovrHmd_RecenterPose(dev.hmdRef)
+}
+
+func (dev *libovrDevice) trackingState(absTime float64) TrackingState {
+	return newTrackingState(C.dl_ovrHmd_GetTrackingState(dev.hmdRef, C.double(absTime)))
+}
+
+// ****************************************************************************
+// **************************** [ Graphics setup ] ****************************
+// ****************************************************************************
+
+func (dev *libovrDevice) fovTextureSize(eye EyeType, fov FovPort, pixelsPerDisplayPixel float32) Sizei {
+	return newSizei(C.dl_ovrHmd_GetFovTextureSize(dev.hmdRef, C.ovrEyeType(eye), fov.toC(), C.float(pixelsPerDisplayPixel)))
+}
+
+// ****************************************************************************
+// *********************** [ SDK Distortion rendering ] ***********************
+// ****************************************************************************
+
+func (dev *libovrDevice) configureRendering(apiConfig *RenderAPIConfig, distortionCaps uint, eyeFovIn [2]FovPort) ([2]EyeRenderDesc, bool) {
+	_apiConfig := apiConfig.toC()
+	_eyeFovIn := [2]C.ovrFovPort{eyeFovIn[0].toC(), eyeFovIn[1].toC()}
+	eyeRenderDescOut := [2]C.ovrEyeRenderDesc{}
+
+	if C.dl_ovrHmd_ConfigureRendering(dev.hmdRef, &_apiConfig, C.uint(distortionCaps), &_eyeFovIn[0], &eyeRenderDescOut[0]) == 0 {
+		return [2]EyeRenderDesc{}, false
+	}
+
+	return [2]EyeRenderDesc{
+		newEyeRenderDesc(eyeRenderDescOut[0]),
+		newEyeRenderDesc(eyeRenderDescOut[1]),
+	}, true
+}
+
+func (dev *libovrDevice) beginFrame(frameIndex uint) FrameTiming {
+	return newFrameTiming(C.dl_ovrHmd_BeginFrame(dev.hmdRef, C.uint(frameIndex)))
+}
+
+func (dev *libovrDevice) endFrame(renderPose [2]Posef, eyeTexture [2]Texture) {
+	_renderPose := [2]C.ovrPosef{renderPose[0].toC(), renderPose[1].toC()}
+	_eyeTexture := [2]C.ovrTexture{eyeTexture[0].toC(), eyeTexture[1].toC()}
+
+	C.dl_ovrHmd_EndFrame(dev.hmdRef, &_renderPose[0], &_eyeTexture[0])
+}
+
+func (dev *libovrDevice) eyePose(eye EyeType) Posef {
+	return newPosef(C.dl_ovrHmd_GetEyePose(dev.hmdRef, C.ovrEyeType(eye)))
+}
+
+// ****************************************************************************
+// ********************** [ Client Distortion rendering ] *********************
+// ****************************************************************************
+
+func (dev *libovrDevice) renderDesc(eye EyeType, fov FovPort) EyeRenderDesc {
+	return newEyeRenderDesc(C.dl_ovrHmd_GetRenderDesc(dev.hmdRef, C.ovrEyeType(eye), fov.toC()))
+}
+
+func (dev *libovrDevice) createDistortionMesh(eye EyeType, fov FovPort, distortionCaps uint) (*DistortionMesh, bool) {
+	meshData := C.ovrDistortionMesh{}
+
+	if C.dl_ovrHmd_CreateDistortionMesh(dev.hmdRef, C.ovrEyeType(eye), fov.toC(), C.uint(distortionCaps), &meshData) == 0 {
+		return nil, false
+	}
+
+	defer C.dl_ovrHmd_DestroyDistortionMesh(&meshData)
+	return newDistortionMesh(meshData), true
+}
+
+func (dev *libovrDevice) frameTiming(frameIndex uint) FrameTiming {
+	return newFrameTiming(C.dl_ovrHmd_GetFrameTiming(dev.hmdRef, C.uint(frameIndex)))
+}
+
+func (dev *libovrDevice) beginFrameTiming(frameIndex uint) FrameTiming {
+	return newFrameTiming(C.dl_ovrHmd_BeginFrameTiming(dev.hmdRef, C.uint(frameIndex)))
+}
+
+func (dev *libovrDevice) endFrameTiming() {
+	C.dl_ovrHmd_EndFrameTiming(dev.hmdRef)
+}
+
+func (dev *libovrDevice) resetFrameTiming(frameIndex uint) {
+	C.dl_ovrHmd_ResetFrameTiming(dev.hmdRef, C.uint(frameIndex))
+}
+
+func (dev *libovrDevice) eyeTimewarpMatrices(eye EyeType, renderPose Posef) [2]Matrix4f {
+	twmOut := [2]C.ovrMatrix4f{}
+	C.dl_ovrHmd_GetEyeTimewarpMatrices(dev.hmdRef, C.ovrEyeType(eye), renderPose.toC(), &twmOut[0])
+
+	return [2]Matrix4f{newMatrix4f(twmOut[0]), newMatrix4f(twmOut[1])}
+}
+
+// ****************************************************************************
+// ************************ [ Latency Test interface ] ************************
+// ****************************************************************************
+
+func (dev *libovrDevice) processLatencyTest() (*[3]uint, bool) {
+	rgbColorOut := [3]C.uchar{}
+	if C.dl_ovrHmd_ProcessLatencyTest(dev.hmdRef, &rgbColorOut[0]) == 0 {
+		return nil, false
+	}
+
+	return &[3]uint{uint(rgbColorOut[0]), uint(rgbColorOut[1]), uint(rgbColorOut[2])}, true
+}
+
+// The ovrHmd_GetLatencyTestResult function has a bug, where it sends back an
+// empty string when there is no error, instead of a NULL. Work around that.
+func (dev *libovrDevice) latencyTestResult() *string {
+	if str := C.dl_ovrHmd_GetLatencyTestResult(dev.hmdRef); str != nil && C.strlen(str) != 0 {
+		goStr := C.GoString(str)
+		return &goStr
+	}
+
+	return nil
+}
+
+// ****************************************************************************
+// ************** [ Health and Safety Warning Display interface ] *************
+// ****************************************************************************
+
+func (dev *libovrDevice) hswDisplayState() HSWDisplayState {
+	hasWarningState := C.ovrHSWDisplayState{}
+	C.dl_ovrHmd_GetHSWDisplayState(dev.hmdRef, &hasWarningState)
+
+	return newHSWDisplayState(hasWarningState)
+}
+
+func (dev *libovrDevice) dismissHSWDisplay() bool {
+	return C.dl_ovrHmd_DismissHSWDisplay(dev.hmdRef) == 1
+}
+
+// ****************************************************************************
+// **************************** [ Property Access ] ***************************
+// ****************************************************************************
+
+func (dev *libovrDevice) getBool(propertyName string, defaultVal bool) bool {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	return C.dl_ovrHmd_GetBool(dev.hmdRef, _propertyName, ovrBool(defaultVal)) == 1
+}
+
+func (dev *libovrDevice) setBool(propertyName string, value bool) bool {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	return C.dl_ovrHmd_SetBool(dev.hmdRef, _propertyName, ovrBool(value)) == 1
+}
+
+func (dev *libovrDevice) getInt(propertyName string, defaultVal int) int {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	return int(C.dl_ovrHmd_GetInt(dev.hmdRef, _propertyName, C.int(defaultVal)))
+}
+
+func (dev *libovrDevice) setInt(propertyName string, value int) bool {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	return C.dl_ovrHmd_SetInt(dev.hmdRef, _propertyName, C.int(value)) == 1
+}
+
+func (dev *libovrDevice) getFloat(propertyName string, defaultVal float32) float32 {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	return float32(C.dl_ovrHmd_GetFloat(dev.hmdRef, _propertyName, C.float(defaultVal)))
+}
+
+func (dev *libovrDevice) setFloat(propertyName string, value float32) bool {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	return C.dl_ovrHmd_SetFloat(dev.hmdRef, _propertyName, C.float(value)) == 1
+}
+
+func (dev *libovrDevice) getFloatArray(propertyName string, values []float32, arraySize uint) uint {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+
+	if arraySize > uint(len(values)) {
+		arraySize = uint(len(values))
+	}
+
+	if arraySize == 0 {
+		return 0
+	}
+
+	_values := make([]C.float, arraySize)
+	count := uint(C.dl_ovrHmd_GetFloatArray(dev.hmdRef, _propertyName, &_values[0], C.uint(arraySize)))
+
+	for i := uint(0); i < count && i < arraySize; i++ {
+		values[i] = float32(_values[i])
+	}
+
+	return count
+}
+
+func (dev *libovrDevice) setFloatArray(propertyName string, values []float32, arraySize uint) bool {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+
+	if arraySize > uint(len(values)) {
+		arraySize = uint(len(values))
+	}
+
+	// The extra element keeps &_values[0] valid when there are no values.
+	_values := make([]C.float, arraySize+1)
+	for i := uint(0); i < arraySize; i++ {
+		_values[i] = C.float(values[i])
+	}
+
+	return C.dl_ovrHmd_SetFloatArray(dev.hmdRef, _propertyName, &_values[0], C.uint(arraySize)) == 1
+}
+
+func (dev *libovrDevice) getString(propertyName, defaultVal string) string {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	_defaultVal := C.CString(defaultVal)
+	defer C.free(unsafe.Pointer(_defaultVal))
+	return C.GoString(C.dl_ovrHmd_GetString(dev.hmdRef, _propertyName, _defaultVal))
+}
+
+func (dev *libovrDevice) setString(propertyName, value string) bool {
+	_propertyName := C.CString(propertyName)
+	defer C.free(unsafe.Pointer(_propertyName))
+	_value := C.CString(value)
+	defer C.free(unsafe.Pointer(_value))
+	return C.dl_ovrHmd_SetString(dev.hmdRef, _propertyName, _value) == 1
+}
@@ -0,0 +1,390 @@
+//go:build libovr && (linux || darwin)
+
+package ovr
+
+// These tests run the cgo layer against the stub library in testdata/libovr,
+// which is built by TestMain. The stub headers have to be on the include path:
+//
+//	CGO_CFLAGS=-I$PWD/testdata/libovr go test -tags libovr
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// The stub libraries, by the name of their variant.
+var stubLibraries = map[string]string{}
+
+func buildStubLibrary(dir, name string, defines ...string) error {
+	cc := os.Getenv("CC")
+	if cc == "" {
+		cc = "cc"
+	}
+
+	library := filepath.Join(dir, name+".so")
+	args := []string{"-shared", "-fPIC", "-Itestdata/libovr", "-o", library}
+	if runtime.GOOS == "darwin" {
+		args[0] = "-dynamiclib"
+	}
+
+	args = append(args, defines...)
+	args = append(args, "testdata/libovr/ovr_stub.c")
+
+	if output, err := exec.Command(cc, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", err, output)
+	}
+
+	stubLibraries[name] = library
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "ovr-stub")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	variants := map[string][]string{
+		"stub":      nil,
+		"version05": {`-DOVR_STUB_VERSION="libOVR:0.5.0"`},
+		"nohsw":     {"-DOVR_STUB_NO_HSW"},
+		"noattach":  {"-DOVR_STUB_NO_ATTACH_TO_WINDOW"},
+	}
+
+	for name, defines := range variants {
+		if err := buildStubLibrary(dir, name, defines...); err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to build the libOVR stub: %s\n", err)
+			os.RemoveAll(dir)
+			os.Exit(1)
+		}
+	}
+
+	LibraryPath = stubLibraries["stub"]
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// Loads another variant of the stub, and checks the error Initialize()
+// returns. The regular stub is loaded again afterwards.
+func TestLibraryLoading(t *testing.T) {
+	defer func() {
+		UnloadLibrary()
+		LibraryPath = stubLibraries["stub"]
+	}()
+
+	cases := []struct {
+		path   string
+		expErr bool
+	}{
+		{filepath.Join(os.TempDir(), "no-such-libovr.so"), true},
+		{stubLibraries["version05"], true},
+		{stubLibraries["nohsw"], true},
+		{stubLibraries["noattach"], false},
+		{stubLibraries["stub"], false},
+	}
+
+	for _, c := range cases {
+		UnloadLibrary()
+		LibraryPath = c.path
+
+		err := Initialize()
+		if c.expErr && err == nil {
+			t.Errorf("Expected Initialize() to fail with library '%s'", c.path)
+		} else if !c.expErr && err != nil {
+			t.Errorf("Expected Initialize() to succeed with library '%s', instead of '%s'", c.path, err)
+		}
+
+		Shutdown()
+	}
+
+	LibraryPath = stubLibraries["version05"]
+	if err := Initialize(); err == nil {
+		t.Error("Expected Initialize() to fail once LibraryPath changed after the library loaded")
+	}
+
+	if err := InitializeRenderingShim(); err == nil {
+		t.Error("Expected InitializeRenderingShim() to fail once LibraryPath changed after the library loaded")
+	}
+}
+
+// The functions that don't need Initialize() load the library on first use,
+// and fall back to their pure Go versions when it can't be loaded.
+func TestLibraryLoadsOnFirstUse(t *testing.T) {
+	defer func() {
+		UnloadLibrary()
+		LibraryPath = stubLibraries["stub"]
+	}()
+
+	fov := FovPort{UpTan: 1.3292863, DownTan: 1.3292863, LeftTan: 1.0586576, RightTan: 1.092368}
+
+	for _, path := range []string{stubLibraries["stub"], filepath.Join(os.TempDir(), "no-such-libovr.so")} {
+		if err := UnloadLibrary(); err != nil {
+			t.Fatalf("Expected UnloadLibrary() to succeed, instead of '%s'", err)
+		}
+		LibraryPath = path
+
+		if calc, exp := Matrix4f_Projection(fov, 0.1, 1000, true), projection(fov, 0.1, 1000, true); calc != exp {
+			t.Errorf("With library '%s', expected the projection %v, instead of %v", path, exp, calc)
+		}
+
+		if now := GetTimeInSeconds(); now <= 0 {
+			t.Errorf("With library '%s', expected a running clock, instead of %f", path, now)
+		}
+	}
+
+	if version := GetVersionString(); version != "" {
+		t.Errorf("Expected no version without the library, instead of '%s'", version)
+	}
+
+	LibraryPath = stubLibraries["stub"]
+	if version := GetVersionString(); version[0:10] != "libOVR:0.4" {
+		t.Errorf("Expected the library to load once LibraryPath is fixed, instead of version '%s'", version)
+	}
+}
+
+func TestLibraryLoadsConcurrently(t *testing.T) {
+	defer Shutdown()
+
+	if err := UnloadLibrary(); err != nil {
+		t.Fatalf("Expected UnloadLibrary() to succeed, instead of '%s'", err)
+	}
+
+	errs := make(chan error, 3)
+	go func() { errs <- Initialize() }()
+	go func() { errs <- InitializeRenderingShim() }()
+	go func() {
+		GetTimeInSeconds()
+		errs <- nil
+	}()
+
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Expected the library to load, instead of '%s'", err)
+		}
+	}
+}
+
+// ****************************************************************************
+// ************************** [ Conversion round-trips ] **********************
+// ****************************************************************************
+
+func TestConversionRoundTrips(t *testing.T) {
+	pose := Posef{Orientation: Quatf{0.1, 0.2, 0.3, 0.9}, Position: Vector3f{1, 2, 3}}
+	if result := newPosef(pose.toC()); result != pose {
+		t.Errorf("Expected Posef %v, instead of %v", pose, result)
+	}
+
+	fov := FovPort{UpTan: 1.1, DownTan: 1.2, LeftTan: 1.3, RightTan: 1.4}
+	if result := newFovPort(fov.toC()); result != fov {
+		t.Errorf("Expected FovPort %v, instead of %v", fov, result)
+	}
+
+	desc := EyeRenderDesc{
+		Eye:                       Eye_Right,
+		Fov:                       fov,
+		DistortedViewport:         Recti{Pos: Vector2i{1, 2}, Size: Sizei{3, 4}},
+		PixelsPerTanAngleAtCenter: Vector2f{5, 6},
+		ViewAdjust:                Vector3f{7, 8, 9},
+	}
+	if result := newEyeRenderDesc(desc.toC()); result != desc {
+		t.Errorf("Expected EyeRenderDesc %v, instead of %v", desc, result)
+	}
+
+	texture := Texture{
+		Header: TextureHeader{
+			API:            RenderAPI_OpenGL,
+			TextureSize:    Sizei{1024, 512},
+			RenderViewport: Recti{Pos: Vector2i{512, 0}, Size: Sizei{512, 512}},
+		},
+		PlatformData: [8]uintptr{1, 2, 3, 4, 5, 6, 7, 8},
+	}
+	if result := newTexture(texture.toC()); result != texture {
+		t.Errorf("Expected Texture %v, instead of %v", texture, result)
+	}
+
+	matrix := Matrix4f{}
+	for i := 0; i < 16; i++ {
+		matrix.M[i/4][i%4] = float32(i)
+	}
+	if result := newMatrix4f(matrix.toC()); result != matrix {
+		t.Errorf("Expected Matrix4f %v, instead of %v", matrix, result)
+	}
+}
+
+// ****************************************************************************
+// **************************** [ Calls into the stub ] ***********************
+// ****************************************************************************
+
+func TestStubFloatArray(t *testing.T) {
+	hmd := initializeWithHmd()
+	defer destroyAndShutdown(hmd)
+
+	if !hmd.SetFloatArray("Test", []float32{1.5, 2.5, 3.5}, 3) {
+		t.Fatal("Expected SetFloatArray() to succeed")
+	}
+
+	values := make([]float32, 4)
+	if count := hmd.GetFloatArray("Test", values, 4); count != 3 {
+		t.Fatalf("Expected GetFloatArray() to return 3 values, instead of %d", count)
+	}
+
+	if values[0] != 1.5 || values[1] != 2.5 || values[2] != 3.5 || values[3] != 0 {
+		t.Errorf("Expected the values [1.5 2.5 3.5 0], instead of %v", values)
+	}
+
+	if count := hmd.GetFloatArray("Test", values, 10); count != 3 {
+		t.Errorf("Expected GetFloatArray() to stop at the length of the slice, instead of returning %d", count)
+	}
+}
+
+func TestStubEndFrame(t *testing.T) {
+	hmd := initializeWithHmd()
+	defer destroyAndShutdown(hmd)
+
+	renderPose := [2]Posef{
+		{Orientation: Quatf{0, 0.5, 0, 0.75}, Position: Vector3f{-0.032, 0, 0}},
+		{Orientation: Quatf{0, -0.5, 0, 0.75}, Position: Vector3f{0.032, 0, 0}},
+	}
+
+	eyeTexture := [2]Texture{}
+	for eye := 0; eye < 2; eye++ {
+		eyeTexture[eye].Header.API = RenderAPI_OpenGL
+		eyeTexture[eye].Header.TextureSize = Sizei{2364, 1461}
+		eyeTexture[eye].Header.RenderViewport = Recti{Pos: Vector2i{eye * 1182, 0}, Size: Sizei{1182, 1461}}
+		eyeTexture[eye].PlatformData[0] = uintptr(eye + 7)
+	}
+
+	hmd.EndFrame(renderPose, eyeTexture)
+
+	poses := make([]float32, 14)
+	hmd.GetFloatArray("StubRenderPose", poses, 14)
+
+	for eye, pose := range renderPose {
+		p := poses[eye*7:]
+		result := Posef{Orientation: Quatf{p[0], p[1], p[2], p[3]}, Position: Vector3f{p[4], p[5], p[6]}}
+		if result != pose {
+			t.Errorf("Expected the stub to receive render pose %v, instead of %v", pose, result)
+		}
+	}
+
+	textures := make([]float32, 16)
+	hmd.GetFloatArray("StubEyeTexture", textures, 16)
+
+	expTextures := []float32{
+		RenderAPI_OpenGL, 2364, 1461, 0, 0, 1182, 1461, 7,
+		RenderAPI_OpenGL, 2364, 1461, 1182, 0, 1182, 1461, 8,
+	}
+
+	for i := range expTextures {
+		if textures[i] != expTextures[i] {
+			t.Errorf("Expected the stub to receive eye textures %v, instead of %v", expTextures, textures)
+			break
+		}
+	}
+}
+
+func TestStubConfigureRendering(t *testing.T) {
+	hmd := initializeWithHmd()
+	defer destroyAndShutdown(hmd)
+
+	renderConfig := GLConfig{}
+	renderConfig.OGL.Header.API = RenderAPI_D311
+
+	if _, err := hmd.ConfigureRendering(renderConfig.Config(), hmd.DistortionCaps, hmd.DefaultEyeFov); err == nil || err.Error() != "Unsupported render API" {
+		t.Errorf("Expected ConfigureRendering() to fail with the error of the stub, instead of '%v'", err)
+	}
+
+	renderConfig.OGL.Header.API = RenderAPI_OpenGL
+
+	eyeRenderDesc, err := hmd.ConfigureRendering(renderConfig.Config(), hmd.DistortionCaps, hmd.DefaultEyeFov)
+	if err != nil {
+		t.Fatalf("Expected ConfigureRendering() to succeed, instead of '%s'", err)
+	}
+
+	for eye := 0; eye < 2; eye++ {
+		if eyeRenderDesc[eye].Eye != EyeType(eye) || eyeRenderDesc[eye].Fov != hmd.DefaultEyeFov[eye] {
+			t.Errorf("Expected the render description of eye %d to match its FOV, instead of %v", eye, eyeRenderDesc[eye])
+		}
+	}
+
+	if eyeRenderDesc[1].DistortedViewport.Pos.X != hmd.Resolution.W/2 || eyeRenderDesc[1].ViewAdjust.X >= 0 {
+		t.Errorf("Expected the right eye to get the right half of the screen, instead of %v", eyeRenderDesc[1])
+	}
+}
+
+func TestStubDistortionMesh(t *testing.T) {
+	hmd := initializeWithHmd()
+	defer destroyAndShutdown(hmd)
+
+	meshData, err := hmd.CreateDistortionMesh(Eye_Left, hmd.DefaultEyeFov[0], hmd.DistortionCaps)
+	if err != nil {
+		t.Fatalf("Expected CreateDistortionMesh() to succeed, instead of '%s'", err)
+	}
+
+	if len(meshData.VertexData) != 9 || len(meshData.IndexData) != 24 {
+		t.Fatalf("Expected a mesh of 9 vertices and 24 indices, instead of %d and %d", len(meshData.VertexData), len(meshData.IndexData))
+	}
+
+	if vertex := meshData.VertexData[8]; vertex.ScreenPosNDC != (Vector2f{1, 1}) || vertex.TimeWarpFactor != 1 || vertex.VignetteFactor != 1 {
+		t.Errorf("Expected the last vertex in the bottom right corner, instead of %v", vertex)
+	}
+
+	if meshData.IndexData[4] != 4 {
+		t.Errorf("Expected index 4 to be 4, instead of %d", meshData.IndexData[4])
+	}
+}
+
+func TestStubMatchesPureGoMath(t *testing.T) {
+	fov := FovPort{UpTan: 1.3292863, DownTan: 1.3292863, LeftTan: 1.0586576, RightTan: 1.092368}
+
+	if stub, pure := Matrix4f_Projection(fov, 0.1, 1000, true), projection(fov, 0.1, 1000, true); stub != pure {
+		t.Errorf("Expected the projection of the stub %v to match %v", stub, pure)
+	}
+
+	viewport := Recti{Pos: Vector2i{1182, 0}, Size: Sizei{1182, 1461}}
+	textureSize := Sizei{2364, 1461}
+
+	if stub, pure := currentBackend.renderScaleAndOffset(fov, textureSize, viewport), renderScaleAndOffset(fov, textureSize, viewport); stub != pure {
+		t.Errorf("Expected the scale and offset of the stub %v to match %v", stub, pure)
+	}
+}
+
+// The stub reports a DK2 at index 0, which it lets be created more than once.
+func TestDevices(t *testing.T) {
+	if err := Initialize(); err != nil {
+		t.Fatalf("Expected Initialize() to succeed, instead of '%s'", err)
+	}
+	defer Shutdown()
+
+	devices := Devices()
+	if len(devices) != 1 || devices[0].Described || devices[0].Captured {
+		t.Fatalf("Expected a device that isn't described until opened, instead of %+v", devices)
+	}
+
+	first, second := HmdCreate(0), HmdCreate(0)
+	if first == nil || second == nil {
+		t.Fatal("Expected HmdCreate(0) to open the DK2")
+	}
+	defer second.Destroy()
+
+	if info := Devices()[0]; !info.Described || !info.Captured || info.Type != Hmd_DK2 || info.SerialNumber != first.SerialNumber {
+		t.Errorf("Expected the open DK2 to be described, instead of %+v", info)
+	}
+
+	first.Destroy()
+
+	if info := Devices()[0]; !info.Described || !info.Captured {
+		t.Errorf("Expected the DK2 to stay described while the second handle is open, instead of %+v", info)
+	}
+
+	if _, err := AttachSimulatedHmd(Hmd_DK2); err == nil {
+		t.Error("Expected AttachSimulatedHmd() to fail with the libovr backend")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build libovr
+
+package ovr
+
+/*
+#include "libovr_loader.h"
+*/
+import "C"
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func (dev *libovrDevice) attachToWindow(hwnd syscall.Handle) bool {
+	return C.dl_ovrHmd_AttachToWindow(dev.hmdRef, unsafe.Pointer(uintptr(hwnd)), nil, nil) == 1
+}
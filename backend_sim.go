@@ -0,0 +1,106 @@
+//go:build !libovr
+
+package ovr
+
+// The simBackend is used when the package is built without the libovr build
+// tag. It has no hardware attached, so HmdDetect() reports zero devices until
+// simulated ones are attached with AttachSimulatedHmd(). HmdCreateDebug() hands
+// out simulated devices just like the SDK does.
+type simBackend struct{}
+
+// Set in the builds where the simulated devices attached with
+// AttachSimulatedHmd() are the attached hardware.
+const simulatedHardware = true
+
+func newBackend() backend {
+	return simBackend{}
+}
+
+func (simBackend) initializeRenderingShim() error {
+	return nil
+}
+
+func (simBackend) initialize() error {
+	return nil
+}
+
+func (simBackend) shutdown() {}
+
+func (simBackend) unloadLibrary() error {
+	return nil
+}
+
+func (simBackend) versionString() string {
+	return "libOVR:0.4.1 (simulated)"
+}
+
+func (simBackend) detect() int {
+	simAttached.Lock()
+	defer simAttached.Unlock()
+
+	return len(simAttached.devices)
+}
+
+// Like the SDK, an attached device can only be opened once at a time.
+func (simBackend) create(index int) *Hmd {
+	simAttached.Lock()
+	defer simAttached.Unlock()
+
+	if index < 0 || index >= len(simAttached.devices) {
+		return nil
+	}
+
+	attachment := simAttached.devices[index]
+	if attachment.captured {
+		return nil
+	}
+
+	attachment.captured = true
+
+	sim := newSimulator(attachment.profile)
+	sim.onDestroy = func() {
+		simAttached.Lock()
+		defer simAttached.Unlock()
+
+		attachment.captured = false
+	}
+
+	return sim.hmd()
+}
+
+func (simBackend) createDebug(hmdType HmdType) *Hmd {
+	return HmdCreateSimulated(hmdType)
+}
+
+func (simBackend) devices() []DeviceInfo {
+	simAttached.Lock()
+	defer simAttached.Unlock()
+
+	infos := []DeviceInfo{}
+	for index, attachment := range simAttached.devices {
+		desc := attachment.profile.desc
+		infos = append(infos, newDeviceInfo(index, &desc, attachment.captured))
+	}
+
+	return infos
+}
+
+func (simBackend) renderScaleAndOffset(fov FovPort, textureSize Sizei, renderViewport Recti) [2]Vector2f {
+	return renderScaleAndOffset(fov, textureSize, renderViewport)
+}
+
+func (simBackend) projection(fov FovPort, znear float32, zfar float32, rightHanded bool) Matrix4f {
+	return projection(fov, znear, zfar, rightHanded)
+}
+
+func (simBackend) orthoSubProjection(projection Matrix4f, orthoScale Vector2f, orthoDistance float32, eyeViewAdjustX float32) Matrix4f {
+	return OrthoSubProjection(projection, orthoScale, orthoDistance, eyeViewAdjustX)
+}
+
+func (simBackend) timeInSeconds() float64 {
+	return goTimeInSeconds()
+}
+
+func (simBackend) waitTillTime(absTime float64) float64 {
+	return goWaitTillTime(absTime)
+}
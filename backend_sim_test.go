@@ -0,0 +1,71 @@
+//go:build !libovr
+
+package ovr
+
+import "testing"
+
+func TestDevices(t *testing.T) {
+	defer DetachSimulatedHmds()
+
+	if devices := Devices(); len(devices) != 0 {
+		t.Fatalf("Expected no devices to be attached, instead of %d", len(devices))
+	}
+
+	for i, hmdType := range []HmdType{Hmd_DK1, Hmd_DK2} {
+		if index, err := AttachSimulatedHmd(hmdType); index != i || err != nil {
+			t.Fatalf("Expected the device to be attached at index %d, instead of %d (%v)", i, index, err)
+		}
+	}
+
+	if index, err := AttachSimulatedHmd(Hmd_Other); index != -1 || err == nil {
+		t.Errorf("Expected AttachSimulatedHmd(Hmd_Other) to fail, instead of returning %d", index)
+	}
+
+	if numDevices := HmdDetect(); numDevices != 2 {
+		t.Fatalf("Expected 2 devices to be attached, instead of %d", numDevices)
+	}
+
+	devices := Devices()
+	if len(devices) != 2 {
+		t.Fatalf("Expected 2 device descriptors, instead of %d", len(devices))
+	}
+
+	if devices[1].Index != 1 || devices[1].Type != Hmd_DK2 || devices[1].ProductName != "Oculus Rift DK2" {
+		t.Errorf("Expected the second device to be a DK2 at index 1, instead of %+v", devices[1])
+	}
+
+	if devices[0].SerialNumber == devices[1].SerialNumber {
+		t.Errorf("Expected the devices to have different serial numbers, instead of '%s'", devices[0].SerialNumber)
+	}
+
+	if !devices[1].Described || devices[1].FirmwareMajor != 2 || devices[1].Captured {
+		t.Errorf("Expected an uncaptured DK2 with firmware 2.x, instead of %+v", devices[1])
+	}
+
+	hmd := HmdCreate(1)
+	if hmd == nil {
+		t.Fatal("Expected HmdCreate(1) to open the DK2")
+	}
+
+	if hmd.SerialNumber != devices[1].SerialNumber {
+		t.Errorf("Expected serial number '%s', instead of '%s'", devices[1].SerialNumber, hmd.SerialNumber)
+	}
+
+	if !Devices()[1].Captured || Devices()[0].Captured {
+		t.Error("Expected only the open device to be captured")
+	}
+
+	if HmdCreate(1) != nil {
+		t.Error("Expected HmdCreate() to fail on a captured device")
+	}
+
+	hmd.Destroy()
+
+	if Devices()[1].Captured {
+		t.Error("Expected the device to be released when destroyed")
+	}
+
+	if HmdCreate(2) != nil {
+		t.Error("Expected HmdCreate() to fail past the last device")
+	}
+}
@@ -0,0 +1,34 @@
+package ovr
+
+import "time"
+
+// A clock for the backends that don't have the C-API available. Like the
+// SDK, it counts seconds from the moment the package was loaded.
+var timeBase = time.Now()
+
+func goTimeInSeconds() float64 {
+	return time.Since(timeBase).Seconds()
+}
+
+// How long before the target time goWaitTillTime() stops sleeping. Sleeps
+// overshoot by a millisecond or more on a busy machine, so this is kept well
+// above that.
+const waitSpinSeconds = 0.005
+
+// Like ovr_WaitTillTime, this returns the amount of time waited. Most of the
+// wait is spent sleeping, and the last few milliseconds are spun away to get
+// the same accuracy as the SDK.
+func goWaitTillTime(absTime float64) float64 {
+	initialTime := goTimeInSeconds()
+
+	if remaining := absTime - initialTime; remaining > waitSpinSeconds {
+		time.Sleep(time.Duration((remaining - waitSpinSeconds) * float64(time.Second)))
+	}
+
+	newTime := goTimeInSeconds()
+	for newTime < absTime {
+		newTime = goTimeInSeconds()
+	}
+
+	return newTime - initialTime
+}
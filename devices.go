@@ -0,0 +1,41 @@
+package ovr
+
+// A DeviceInfo describes an attached device, without having to open it.
+type DeviceInfo struct {
+	Index int
+
+	// Set if the fields below are known. libOVR can only describe a device
+	// once it is open, so the devices that aren't open in this program are
+	// listed with their Index alone.
+	Described bool
+
+	Type          HmdType
+	ProductName   string
+	SerialNumber  string
+	FirmwareMajor int
+	FirmwareMinor int
+
+	// Set if the device is already in use, either by this program or by
+	// another one (HmdCap_Captured). With libOVR, only the devices this
+	// program opened are known to be captured.
+	Captured bool
+}
+
+func newDeviceInfo(index int, hmd *Hmd, captured bool) DeviceInfo {
+	return DeviceInfo{
+		Index:         index,
+		Described:     true,
+		Type:          hmd.Type,
+		ProductName:   hmd.ProductName,
+		SerialNumber:  hmd.SerialNumber,
+		FirmwareMajor: hmd.FirmwareMajor,
+		FirmwareMinor: hmd.FirmwareMinor,
+		Captured:      captured || hmd.HmdCaps&HmdCap_Captured != 0,
+	}
+}
+
+// Devices returns a description of every attached device, in the order of the
+// index that HmdCreate() takes. Debug and simulated devices are not included.
+func Devices() []DeviceInfo {
+	return currentBackend.devices()
+}
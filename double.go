@@ -0,0 +1,137 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// *************************** [ Double precision ] ***************************
+// ****************************************************************************
+
+// A 3D vector with double components, for positions far from the origin.
+type Vector3d struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// A quaternion rotation with double components.
+type Quatd struct {
+	X float64
+	Y float64
+	Z float64
+	W float64
+}
+
+// Position and orientation together, in double precision.
+type Posed struct {
+	Orientation Quatd
+	Position    Vector3d
+}
+
+// Conversions from float to double are lossless. The other way round, values
+// are rounded to the nearest float.
+
+func (v Vector3f) Vector3d() Vector3d {
+	return Vector3d{float64(v.X), float64(v.Y), float64(v.Z)}
+}
+
+func (v Vector3d) Vector3f() Vector3f {
+	return Vector3f{float32(v.X), float32(v.Y), float32(v.Z)}
+}
+
+func (q Quatf) Quatd() Quatd {
+	return Quatd{float64(q.X), float64(q.Y), float64(q.Z), float64(q.W)}
+}
+
+func (q Quatd) Quatf() Quatf {
+	return Quatf{float32(q.X), float32(q.Y), float32(q.Z), float32(q.W)}
+}
+
+func (pose Posef) Posed() Posed {
+	return Posed{Orientation: pose.Orientation.Quatd(), Position: pose.Position.Vector3d()}
+}
+
+func (pose Posed) Posef() Posef {
+	return Posef{Orientation: pose.Orientation.Quatf(), Position: pose.Position.Vector3f()}
+}
+
+func (v Vector3d) Add(w Vector3d) Vector3d {
+	return Vector3d{v.X + w.X, v.Y + w.Y, v.Z + w.Z}
+}
+
+func (v Vector3d) Sub(w Vector3d) Vector3d {
+	return Vector3d{v.X - w.X, v.Y - w.Y, v.Z - w.Z}
+}
+
+func (v Vector3d) Scale(s float64) Vector3d {
+	return Vector3d{v.X * s, v.Y * s, v.Z * s}
+}
+
+func (v Vector3d) Length() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
+
+// Mul returns the rotation r followed by q.
+func (q Quatd) Mul(r Quatd) Quatd {
+	return Quatd{
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+	}
+}
+
+// Conjugate returns the conjugate of q, which is its inverse if q is
+// normalized.
+func (q Quatd) Conjugate() Quatd {
+	return Quatd{-q.X, -q.Y, -q.Z, q.W}
+}
+
+// Normalize returns q scaled to a length of one, or q itself if it has a
+// length of zero.
+func (q Quatd) Normalize() Quatd {
+	length := math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z + q.W*q.W)
+	if length == 0 {
+		return q
+	}
+
+	return Quatd{q.X / length, q.Y / length, q.Z / length, q.W / length}
+}
+
+// Rotate returns v rotated by q, which should be normalized.
+func (q Quatd) Rotate(v Vector3d) Vector3d {
+	r := q.Mul(Quatd{v.X, v.Y, v.Z, 0}).Mul(q.Conjugate())
+	return Vector3d{r.X, r.Y, r.Z}
+}
+
+// Mul returns the pose child, given in the frame of pose, in the frame pose is
+// given in.
+func (pose Posed) Mul(child Posed) Posed {
+	return Posed{
+		Orientation: pose.Orientation.Mul(child.Orientation),
+		Position:    pose.Position.Add(pose.Orientation.Rotate(child.Position)),
+	}
+}
+
+// Transform returns the point v, given in the frame of pose, in the frame pose
+// is given in.
+func (pose Posed) Transform(v Vector3d) Vector3d {
+	return pose.Position.Add(pose.Orientation.Rotate(v))
+}
+
+// Inverse returns the pose that undoes pose.
+func (pose Posed) Inverse() Posed {
+	orientation := pose.Orientation.Conjugate()
+	return Posed{Orientation: orientation, Position: orientation.Rotate(pose.Position).Scale(-1)}
+}
+
+// ComposeWorldPose places the head pose from the SDK in the world with
+// worldPose, in double precision, and returns it relative to origin. Pick an
+// origin close to the player, like the position of worldPose, and render the
+// scene relative to it, so the float result keeps its precision however far
+// the player is from the origin of the world.
+func ComposeWorldPose(worldPose Posed, headPose Posef, origin Vector3d) Posef {
+	pose := worldPose.Mul(headPose.Posed())
+	pose.Position = pose.Position.Sub(origin)
+
+	return pose.Posef()
+}
@@ -0,0 +1,64 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPosedConversions(t *testing.T) {
+	pose := Posef{Orientation: QuatfFromAxisAngle(Vector3f{1, 2, 3}, 0.7), Position: Vector3f{0.1, 1.7, -0.3}}
+
+	if result := pose.Posed().Posef(); result != pose {
+		t.Errorf("Expected the conversion to double and back to be lossless, instead of %v from %v", result, pose)
+	}
+}
+
+func TestPosedAlgebra(t *testing.T) {
+	world := Posed{
+		Orientation: Quatd{Y: math.Sin(math.Pi / 4), W: math.Cos(math.Pi / 4)},
+		Position:    Vector3d{5e6, 120, -3e6},
+	}
+
+	child := Posed{Orientation: Quatd{W: 1}, Position: Vector3d{0, 0, -1}}
+	result := world.Mul(child)
+
+	// Turned to the left, a meter forward is a meter towards -X.
+	if exp := (Vector3d{5e6 - 1, 120, -3e6}); result.Position.Sub(exp).Length() > 1e-6 {
+		t.Errorf("Expected the child at %v instead of %v", exp, result.Position)
+	}
+
+	if transformed := world.Transform(child.Position); transformed != result.Position {
+		t.Errorf("Expected Transform() to give %v instead of %v", result.Position, transformed)
+	}
+
+	identity := world.Inverse().Mul(world)
+	if identity.Position.Length() > 1e-6 || math.Abs(math.Abs(identity.Orientation.W)-1) > 1e-12 {
+		t.Errorf("Expected the inverse of the pose to undo it, instead of %v", identity)
+	}
+}
+
+func TestComposeWorldPose(t *testing.T) {
+	world := Posed{
+		Orientation: QuatfFromAxisAngle(Vector3f{Y: 1}, 0.4).Quatd(),
+		Position:    Vector3d{5e6, 120, -3e6},
+	}
+
+	headPose := Posef{Orientation: QuatfFromAxisAngle(Vector3f{1, 0, 0}, 0.2), Position: Vector3f{0.032, 0.01, -0.005}}
+
+	// Far from the origin, floats are half a meter apart, and the head offset
+	// is lost. Relative to the position of the player, it is kept.
+	result := ComposeWorldPose(world, headPose, world.Position)
+	exp := world.Orientation.Quatf().Rotate(headPose.Position)
+
+	if !approxVector3f(exp, result.Position, 1e-7) {
+		t.Errorf("Expected the head at %v relative to the player, instead of %v", exp, result.Position)
+	}
+
+	if expOrientation := world.Orientation.Quatf().Mul(headPose.Orientation); !approxQuatf(expOrientation, result.Orientation, 1e-6) {
+		t.Errorf("Expected the orientation %v instead of %v", expOrientation, result.Orientation)
+	}
+
+	if lossy := vectorAdd(world.Position.Vector3f(), exp); vectorSub(lossy, world.Position.Vector3f()) == exp {
+		t.Errorf("Expected the head offset to be lost in float precision, instead of %v", lossy)
+	}
+}
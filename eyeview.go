@@ -0,0 +1,65 @@
+package ovr
+
+// ****************************************************************************
+// ******************************** [ Eye views ] *****************************
+// ****************************************************************************
+
+// An EyeView holds what is needed to render the scene for one eye.
+type EyeView struct {
+	Eye EyeType
+
+	// The pose GetEyePose() returned for the eye, in the frame of the
+	// tracking origin. Pass it to EndFrame() as the render pose of the eye.
+	RenderPose Posef
+
+	// The pose of the eye in the world, offset from the head by the
+	// ViewAdjust of the eye.
+	WorldPose Posef
+
+	// The view matrix takes points from the world to the frame of the eye,
+	// and is the inverse of WorldPose. The projection matrix is right-handed,
+	// and the view-projection matrix is both combined.
+	View           Matrix4f
+	Projection     Matrix4f
+	ViewProjection Matrix4f
+}
+
+// GetEyeViews returns the views of both eyes, in EyeRenderOrder. It calls
+// GetEyePose() for each eye in that order, so it has to be called between
+// BeginFrame() and EndFrame() like GetEyePose().
+//
+// worldPose places the tracking origin in the world, for example at the
+// position and heading of the player. eyeRenderDesc is indexed by eye, like
+// ConfigureRendering() returns it. The projections are made by Projection(),
+// with Projection_RightHanded added to modifier.
+func (hmd *Hmd) GetEyeViews(worldPose Posef, eyeRenderDesc [Eye_Count]EyeRenderDesc, znear, zfar float32, modifier ProjectionModifier) [Eye_Count]EyeView {
+	views := [Eye_Count]EyeView{}
+
+	for i, eye := range hmd.EyeRenderOrder {
+		renderPose := hmd.GetEyePose(eye)
+		views[i] = newEyeView(eye, worldPose, renderPose, eyeRenderDesc[eye], znear, zfar, modifier)
+	}
+
+	return views
+}
+
+func newEyeView(eye EyeType, worldPose Posef, renderPose Posef, eyeRenderDesc EyeRenderDesc, znear, zfar float32, modifier ProjectionModifier) EyeView {
+	orientation := worldPose.Orientation.Mul(renderPose.Orientation)
+
+	// ViewAdjust moves the world in the frame of the eye, so the eye itself
+	// is offset the other way.
+	position := vectorAdd(worldPose.Position, worldPose.Orientation.Rotate(renderPose.Position))
+	position = vectorSub(position, orientation.Rotate(eyeRenderDesc.ViewAdjust))
+
+	view := Matrix4f_Rotation(orientation.Conjugate()).Mul(Matrix4f_Translation(vectorScale(position, -1)))
+	projection := Projection(eyeRenderDesc.Fov, znear, zfar, modifier|Projection_RightHanded)
+
+	return EyeView{
+		Eye:            eye,
+		RenderPose:     renderPose,
+		WorldPose:      Posef{Orientation: orientation, Position: position},
+		View:           view,
+		Projection:     projection,
+		ViewProjection: projection.Mul(view),
+	}
+}
@@ -0,0 +1,69 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetEyeViews(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	eyeRenderDesc := [Eye_Count]EyeRenderDesc{
+		hmd.GetRenderDesc(Eye_Left, hmd.DefaultEyeFov[Eye_Left]),
+		hmd.GetRenderDesc(Eye_Right, hmd.DefaultEyeFov[Eye_Right]),
+	}
+
+	// The player stands at (1, 0, 5), turned a quarter to the left.
+	worldPose := Posef{
+		Orientation: QuatfFromAxisAngle(Vector3f{Y: 1}, math.Pi/2),
+		Position:    Vector3f{1, 0, 5},
+	}
+
+	hmd.BeginFrame(0)
+	views := hmd.GetEyeViews(worldPose, eyeRenderDesc, 0.1, 1000, Projection_None)
+
+	renderPose := [2]Posef{}
+	for _, view := range views {
+		renderPose[view.Eye] = view.RenderPose
+	}
+	hmd.EndFrame(renderPose, [2]Texture{})
+
+	for i, view := range views {
+		if view.Eye != hmd.EyeRenderOrder[i] {
+			t.Errorf("Expected view %d to be of eye %d, instead of %d", i, hmd.EyeRenderOrder[i], view.Eye)
+		}
+
+		desc := eyeRenderDesc[view.Eye]
+
+		// The point two meters in front of the player is straight ahead of
+		// the head, so it is offset by ViewAdjust from each eye.
+		ahead := Vector3f{-1, 0, 5}
+		exp := Vector3f{desc.ViewAdjust.X, 0, -2}
+		if result := view.View.Transform(ahead); !approxVector3f(exp, result, 1e-5) {
+			t.Errorf("Expected eye %d to see the point ahead at %v instead of %v", view.Eye, exp, result)
+		}
+
+		if result := Matrix4f_FromPose(view.WorldPose).Mul(view.View); !approxMatrix4f(Matrix4f_Identity(), result, 1e-5) {
+			t.Errorf("Expected the view of eye %d to be the inverse of its world pose, instead of %v", view.Eye, result)
+		}
+
+		if exp := Projection(desc.Fov, 0.1, 1000, Projection_RightHanded); view.Projection != exp {
+			t.Errorf("Expected the projection of eye %d to be %v instead of %v", view.Eye, exp, view.Projection)
+		}
+
+		if exp := view.Projection.Mul(view.View); view.ViewProjection != exp {
+			t.Errorf("Expected the view-projection of eye %d to be %v instead of %v", view.Eye, exp, view.ViewProjection)
+		}
+	}
+
+	// The left eye is to the left of the player, which is towards +Z.
+	left, right := views[0].WorldPose.Position, views[1].WorldPose.Position
+	if views[0].Eye == Eye_Right {
+		left, right = right, left
+	}
+
+	if left.Z <= right.Z || !approxFloat(1, left.X, 1e-6) || !approxFloat(1, right.X, 1e-6) {
+		t.Errorf("Expected the left eye at %v to be to the left of the right eye at %v", left, right)
+	}
+}
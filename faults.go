@@ -0,0 +1,191 @@
+package ovr
+
+import "math/rand"
+
+// A FaultKind is a failure that can be injected into a simulated device.
+type FaultKind int
+
+const (
+	// ConfigureRendering() fails, and GetLastError() returns the message.
+	Fault_ConfigureRendering FaultKind = iota
+
+	// CreateDistortionMesh() fails, and GetLastError() returns the message.
+	Fault_CreateDistortionMesh
+
+	// The camera loses sight of the device. Status_PositionTracked is
+	// cleared, and the position is held where it was last tracked.
+	Fault_PositionLoss
+
+	// The camera is unplugged. Status_PositionConnected,
+	// Status_PositionTracked and Status_CameraPoseTracked are cleared, and
+	// the position is held where it was last tracked.
+	Fault_CameraDisconnect
+
+	// Gaussian noise with a standard deviation of GyroNoise rad/s is added to
+	// the gyro readings.
+	Fault_NoisyGyro
+)
+
+// A Fault schedules a failure of a simulated device. It is active while both
+// its time window and its call window hold; leaving both at zero makes it
+// active right away and for good.
+//
+// The time window is on the clock of GetTimeInSeconds(). The tracking faults
+// compare it to the time the tracking state is asked for, the others to the
+// time of the call.
+//
+// The call window counts the calls to the function the fault affects, from
+// the moment the fault is injected. The tracking faults count the calls to
+// GetTrackingState().
+type Fault struct {
+	Kind FaultKind
+
+	// The fault is active from Start until End. An End of zero means the
+	// fault doesn't end.
+	Start float64
+	End   float64
+
+	// The fault is active for Calls calls, starting with call number
+	// FirstCall, where 1 is the first call. A FirstCall of zero is the same as
+	// 1, and a Calls of zero means every call after that.
+	FirstCall int
+	Calls     int
+
+	// The message returned by GetLastError() when a call fails. A default
+	// message is used when it is empty.
+	Message string
+
+	// The standard deviation of the noise of Fault_NoisyGyro, in rad/s.
+	GyroNoise float32
+}
+
+var defaultFaultMessages = map[FaultKind]string{
+	Fault_ConfigureRendering:   "Failed to initialize the distortion renderer",
+	Fault_CreateDistortionMesh: "Failed to create the distortion mesh",
+}
+
+// A simFault is an injected fault, along with the number of calls that had
+// already been made to the function it affects when it was injected.
+type simFault struct {
+	Fault
+	callBase int
+}
+
+// The call counters of a simulated device, one per function faults can
+// affect.
+type simCalls struct {
+	configureRendering   int
+	createDistortionMesh int
+	trackingState        int
+}
+
+func (calls *simCalls) count(kind FaultKind) int {
+	switch kind {
+	case Fault_ConfigureRendering:
+		return calls.configureRendering
+	case Fault_CreateDistortionMesh:
+		return calls.createDistortionMesh
+	}
+
+	return calls.trackingState
+}
+
+// InjectFault schedules fault on the simulated device. Several faults can be
+// active at once.
+func (sim *Simulator) InjectFault(fault Fault) {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.faults = append(sim.faults, simFault{Fault: fault, callBase: sim.calls.count(fault.Kind)})
+}
+
+// ClearFaults removes all the faults injected into the simulated device.
+func (sim *Simulator) ClearFaults() {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.faults = nil
+}
+
+// Returns the first fault of the given kind that is active at absTime, or nil
+// if there is none.
+func (sim *Simulator) activeFault(kind FaultKind, absTime float64) *Fault {
+	for i := range sim.faults {
+		fault := &sim.faults[i]
+		if fault.Kind != kind {
+			continue
+		}
+
+		if absTime < fault.Start || (fault.End != 0 && absTime >= fault.End) {
+			continue
+		}
+
+		call := sim.calls.count(kind) - fault.callBase
+		firstCall := max(fault.FirstCall, 1)
+		if call < firstCall || (fault.Calls != 0 && call >= firstCall+fault.Calls) {
+			continue
+		}
+
+		return &fault.Fault
+	}
+
+	return nil
+}
+
+// Fails the current call if a fault of the given kind is active, and sets the
+// last error to its message.
+func (sim *Simulator) failCall(kind FaultKind) bool {
+	fault := sim.activeFault(kind, GetTimeInSeconds())
+	if fault == nil {
+		return false
+	}
+
+	if fault.Message != "" {
+		sim.setLastError(fault.Message)
+	} else {
+		sim.setLastError(defaultFaultMessages[kind])
+	}
+
+	return true
+}
+
+// Applies the active tracking faults to a tracking state at absTime, before
+// recentering.
+func (sim *Simulator) applyFaults(state TrackingState, absTime float64) TrackingState {
+	lost := uint(0)
+
+	if sim.activeFault(Fault_PositionLoss, absTime) != nil {
+		lost |= Status_PositionTracked
+	}
+
+	if sim.activeFault(Fault_CameraDisconnect, absTime) != nil {
+		lost |= Status_PositionConnected | Status_PositionTracked | Status_CameraPoseTracked
+		state.CameraPose = Posef{Orientation: Quatf{W: 1}}
+		state.LeveledCameraPose = state.CameraPose
+	}
+
+	if state.StatusFlags&Status_PositionTracked != 0 {
+		if lost&Status_PositionTracked == 0 {
+			sim.lastTrackedPosition = state.HeadPose.ThePose.Position
+		} else {
+			state.HeadPose.ThePose.Position = sim.lastTrackedPosition
+			state.HeadPose.LinearVelocity = Vector3f{}
+			state.HeadPose.LinearAcceleration = Vector3f{}
+		}
+	}
+
+	state.StatusFlags &^= lost
+
+	if fault := sim.activeFault(Fault_NoisyGyro, absTime); fault != nil {
+		if sim.rand == nil {
+			sim.rand = rand.New(rand.NewSource(1))
+		}
+
+		gyro := &state.RawSensorData.Gyro
+		gyro.X += float32(sim.rand.NormFloat64()) * fault.GyroNoise
+		gyro.Y += float32(sim.rand.NormFloat64()) * fault.GyroNoise
+		gyro.Z += float32(sim.rand.NormFloat64()) * fault.GyroNoise
+	}
+
+	return state
+}
@@ -0,0 +1,106 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFaultConfigureRendering(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	renderConfig := GLConfig{}
+	renderConfig.OGL.Header.API = RenderAPI_OpenGL
+
+	hmd.Simulator().InjectFault(Fault{Kind: Fault_ConfigureRendering, FirstCall: 2, Calls: 1, Message: "Display lost"})
+
+	for call := 1; call <= 3; call++ {
+		_, err := hmd.ConfigureRendering(renderConfig.Config(), hmd.DistortionCaps, hmd.DefaultEyeFov)
+
+		switch {
+		case call == 2 && err == nil:
+			t.Error("Expected the second call to ConfigureRendering() to fail")
+		case call == 2 && err.Error() != "Display lost":
+			t.Errorf("Expected error 'Display lost', instead of '%s'", err)
+		case call != 2 && err != nil:
+			t.Errorf("Expected call %d to ConfigureRendering() to succeed, instead of '%s'", call, err)
+		}
+	}
+}
+
+func TestFaultCreateDistortionMesh(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	now := GetTimeInSeconds()
+	hmd.Simulator().InjectFault(Fault{Kind: Fault_CreateDistortionMesh, Start: now - 1, End: now + 60})
+
+	if _, err := hmd.CreateDistortionMesh(Eye_Left, hmd.DefaultEyeFov[0], hmd.DistortionCaps); err == nil || err.Error() != defaultFaultMessages[Fault_CreateDistortionMesh] {
+		t.Errorf("Expected CreateDistortionMesh() to fail with the default message, instead of '%v'", err)
+	}
+
+	hmd.Simulator().ClearFaults()
+
+	if _, err := hmd.CreateDistortionMesh(Eye_Left, hmd.DefaultEyeFov[0], hmd.DistortionCaps); err != nil {
+		t.Errorf("Expected CreateDistortionMesh() to succeed once the faults are cleared, instead of '%s'", err)
+	}
+}
+
+func TestFaultTracking(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+	hmd.Simulator().SetMotionScript(&MotionScript{
+		Duration: 10,
+		Segments: []MotionSegment{{Motion: FigureEight{Width: 0.3, Height: 0.1, Period: 5}}},
+	}, 0)
+
+	sim := hmd.Simulator()
+	sim.InjectFault(Fault{Kind: Fault_PositionLoss, Start: 2, End: 3})
+	sim.InjectFault(Fault{Kind: Fault_CameraDisconnect, Start: 4, End: 5})
+
+	tracked := hmd.GetTrackingState(1.9)
+	lost := hmd.GetTrackingState(2.5)
+
+	if lost.StatusFlags&Status_PositionTracked != 0 || lost.StatusFlags&Status_PositionConnected == 0 {
+		t.Errorf("Expected only the position tracking to be lost, instead of status 0x%x", lost.StatusFlags)
+	}
+
+	if lost.HeadPose.ThePose.Position != tracked.HeadPose.ThePose.Position {
+		t.Error("Expected the position to be held while tracking is lost")
+	}
+
+	disconnected := hmd.GetTrackingState(4.5)
+	if disconnected.StatusFlags&(Status_PositionConnected|Status_PositionTracked|Status_CameraPoseTracked) != 0 {
+		t.Errorf("Expected the camera to be disconnected, instead of status 0x%x", disconnected.StatusFlags)
+	}
+
+	identity := Posef{Orientation: Quatf{W: 1}}
+	if disconnected.CameraPose != identity || disconnected.LeveledCameraPose != identity {
+		t.Errorf("Expected the camera poses to be the identity, instead of %v and %v", disconnected.CameraPose, disconnected.LeveledCameraPose)
+	}
+
+	if flags := hmd.GetTrackingState(5.5).StatusFlags; flags&Status_PositionTracked == 0 {
+		t.Errorf("Expected the position to be tracked again, instead of status 0x%x", flags)
+	}
+}
+
+func TestFaultNoisyGyro(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.Simulator().InjectFault(Fault{Kind: Fault_NoisyGyro, GyroNoise: 0.05})
+
+	// The device holds still, so the gyro should only measure the noise.
+	const samples = 2000
+	sumSq := 0.0
+	for i := 0; i < samples; i++ {
+		gyro := hmd.GetTrackingState(0).RawSensorData.Gyro
+		sumSq += float64(gyro.X*gyro.X + gyro.Y*gyro.Y + gyro.Z*gyro.Z)
+	}
+
+	if deviation := float32(math.Sqrt(sumSq / (3 * samples))); !approxFloat(0.05, deviation, 0.005) {
+		t.Errorf("Expected a gyro noise of 0.05 rad/s, instead of %f", deviation)
+	}
+}
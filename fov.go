@@ -0,0 +1,77 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ********************************* [ FovPort ] ******************************
+// ****************************************************************************
+
+// FovPortFromRadians returns the symmetric FOV with the given horizontal and
+// vertical angles in radians.
+func FovPortFromRadians(horizontal, vertical float32) FovPort {
+	horizontalTan := float32(math.Tan(float64(horizontal) / 2))
+	verticalTan := float32(math.Tan(float64(vertical) / 2))
+
+	return FovPort{UpTan: verticalTan, DownTan: verticalTan, LeftTan: horizontalTan, RightTan: horizontalTan}
+}
+
+// FovPortFromDegrees returns the symmetric FOV with the given horizontal and
+// vertical angles in degrees.
+func FovPortFromDegrees(horizontal, vertical float32) FovPort {
+	return FovPortFromRadians(horizontal*math.Pi/180, vertical*math.Pi/180)
+}
+
+// HorizontalRadians returns the angle between the left and right edge of fov.
+func (fov FovPort) HorizontalRadians() float32 {
+	return float32(math.Atan(float64(fov.LeftTan)) + math.Atan(float64(fov.RightTan)))
+}
+
+// VerticalRadians returns the angle between the top and bottom edge of fov.
+func (fov FovPort) VerticalRadians() float32 {
+	return float32(math.Atan(float64(fov.UpTan)) + math.Atan(float64(fov.DownTan)))
+}
+
+func (fov FovPort) HorizontalDegrees() float32 {
+	return fov.HorizontalRadians() * 180 / math.Pi
+}
+
+func (fov FovPort) VerticalDegrees() float32 {
+	return fov.VerticalRadians() * 180 / math.Pi
+}
+
+// Union returns the smallest FOV that covers both fov and other. The union of
+// the FOV of both eyes covers everything either eye can see, from the center
+// of the head.
+func (fov FovPort) Union(other FovPort) FovPort {
+	return FovPort{
+		UpTan:    max(fov.UpTan, other.UpTan),
+		DownTan:  max(fov.DownTan, other.DownTan),
+		LeftTan:  max(fov.LeftTan, other.LeftTan),
+		RightTan: max(fov.RightTan, other.RightTan),
+	}
+}
+
+// Clamp returns fov with each side limited to that of limit, which is usually
+// the MaxEyeFov of the eye.
+func (fov FovPort) Clamp(limit FovPort) FovPort {
+	return FovPort{
+		UpTan:    min(fov.UpTan, limit.UpTan),
+		DownTan:  min(fov.DownTan, limit.DownTan),
+		LeftTan:  min(fov.LeftTan, limit.LeftTan),
+		RightTan: min(fov.RightTan, limit.RightTan),
+	}
+}
+
+// Within returns whether no side of fov goes beyond that of limit.
+func (fov FovPort) Within(limit FovPort) bool {
+	return fov.Clamp(limit) == fov
+}
+
+// Symmetric returns the smallest FOV that covers fov and whose left and right,
+// and up and down, sides are the same.
+func (fov FovPort) Symmetric() FovPort {
+	horizontalTan := max(fov.LeftTan, fov.RightTan)
+	verticalTan := max(fov.UpTan, fov.DownTan)
+
+	return FovPort{UpTan: verticalTan, DownTan: verticalTan, LeftTan: horizontalTan, RightTan: horizontalTan}
+}
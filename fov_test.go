@@ -0,0 +1,68 @@
+package ovr
+
+import "testing"
+
+func TestFovPortDegrees(t *testing.T) {
+	fov := FovPortFromDegrees(90, 60)
+
+	if !approxFloat(1, fov.LeftTan, 1e-6) || !approxFloat(1, fov.RightTan, 1e-6) ||
+		!approxFloat(0.57735026, fov.UpTan, 1e-6) || !approxFloat(0.57735026, fov.DownTan, 1e-6) {
+		t.Errorf("Expected the tangents of 45° and 30° instead of %v", fov)
+	}
+
+	if horizontal, vertical := fov.HorizontalDegrees(), fov.VerticalDegrees(); !approxFloat(90, horizontal, 1e-4) || !approxFloat(60, vertical, 1e-4) {
+		t.Errorf("Expected a FOV of 90° by 60° instead of %f° by %f°", horizontal, vertical)
+	}
+
+	// An asymmetric FOV adds up the angles of both sides.
+	asymmetric := FovPort{UpTan: 1, DownTan: 0, LeftTan: 1, RightTan: 0.57735026}
+	if horizontal, vertical := asymmetric.HorizontalDegrees(), asymmetric.VerticalDegrees(); !approxFloat(75, horizontal, 1e-4) || !approxFloat(45, vertical, 1e-4) {
+		t.Errorf("Expected a FOV of 75° by 45° instead of %f° by %f°", horizontal, vertical)
+	}
+}
+
+func TestFovPortUnionAndSymmetric(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK1)
+	defer hmd.Destroy()
+
+	left, right := hmd.DefaultEyeFov[Eye_Left], hmd.DefaultEyeFov[Eye_Right]
+	union := left.Union(right)
+
+	if !left.Within(union) || !right.Within(union) {
+		t.Errorf("Expected the union %v to cover %v and %v", union, left, right)
+	}
+
+	if union.LeftTan != left.LeftTan || union.RightTan != right.RightTan {
+		t.Errorf("Expected the union to take the outer side of each eye instead of %v", union)
+	}
+
+	symmetric := left.Symmetric()
+	if !left.Within(symmetric) || symmetric.LeftTan != symmetric.RightTan || symmetric.UpTan != symmetric.DownTan {
+		t.Errorf("Expected a symmetric FOV that covers %v instead of %v", left, symmetric)
+	}
+
+	if symmetric.LeftTan != left.LeftTan {
+		t.Errorf("Expected the symmetric FOV to take the widest side %f instead of %f", left.LeftTan, symmetric.LeftTan)
+	}
+}
+
+func TestFovPortClamp(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	limit := hmd.MaxEyeFov[Eye_Left]
+
+	if fov := FovPortFromDegrees(60, 60); !fov.Within(limit) || fov.Clamp(limit) != fov {
+		t.Errorf("Expected a FOV of 60° by 60° to be within the limits %v", limit)
+	}
+
+	fov := FovPortFromDegrees(150, 120)
+	if fov.Within(limit) {
+		t.Errorf("Expected a FOV of 150° by 120° to go beyond the limits %v", limit)
+	}
+
+	clamped := fov.Clamp(limit)
+	if clamped != limit.Clamp(fov) || !clamped.Within(limit) || !clamped.Within(fov) {
+		t.Errorf("Expected the FOV to be clamped to %v instead of %v", limit, clamped)
+	}
+}
@@ -0,0 +1,195 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ********************************* [ Planes ] *******************************
+// ****************************************************************************
+
+// A Plane holds the points p where Normal·p + D = 0. Normal has a length of
+// one, and points in front of the plane are at a positive distance.
+type Plane struct {
+	Normal Vector3f
+	D      float32
+}
+
+// Returns the plane through point with the given normal, which doesn't have to
+// be normalized.
+func newPlane(normal, point Vector3f) Plane {
+	normal = vectorNormalize(normal)
+	return Plane{Normal: normal, D: -vectorDot(normal, point)}
+}
+
+// Distance returns the signed distance of p to the plane, which is positive in
+// front of it.
+func (plane Plane) Distance(p Vector3f) float32 {
+	return vectorDot(plane.Normal, p) + plane.D
+}
+
+// Transform returns the plane moved from the frame of pose to the frame the
+// pose is given in.
+func (plane Plane) Transform(pose Posef) Plane {
+	normal := pose.Orientation.Rotate(plane.Normal)
+	return Plane{Normal: normal, D: plane.D - vectorDot(normal, pose.Position)}
+}
+
+// ****************************************************************************
+// ******************************** [ Frustums ] ******************************
+// ****************************************************************************
+
+// The planes of a Frustum.
+const (
+	Frustum_Left   = 0
+	Frustum_Right  = 1
+	Frustum_Bottom = 2
+	Frustum_Top    = 3
+	Frustum_Near   = 4
+	Frustum_Far    = 5
+	Frustum_Count  = 6
+)
+
+// A Frustum is the volume in view of a camera. Its planes face inwards.
+//
+// The intersection tests are conservative: they never reject an object that
+// is in view, but they may accept one that is just outside a corner of the
+// frustum.
+type Frustum struct {
+	Planes [Frustum_Count]Plane
+}
+
+// FrustumFromFov returns the frustum of a camera at pose, which looks down -Z
+// like libOVR does. For an eye, pass the WorldPose of its EyeView. If zfar is
+// infinite, the frustum has no far plane.
+func FrustumFromFov(fov FovPort, pose Posef, znear, zfar float32) Frustum {
+	return frustumFromFov(fov, Vector3f{}, znear, zfar).Transform(pose)
+}
+
+// Returns the frustum of a camera at apex that looks down -Z. The near and far
+// planes are at -znear and -zfar on the Z axis, wherever the apex is.
+func frustumFromFov(fov FovPort, apex Vector3f, znear, zfar float32) Frustum {
+	frustum := Frustum{}
+	frustum.Planes[Frustum_Left] = newPlane(Vector3f{1, 0, -fov.LeftTan}, apex)
+	frustum.Planes[Frustum_Right] = newPlane(Vector3f{-1, 0, -fov.RightTan}, apex)
+	frustum.Planes[Frustum_Bottom] = newPlane(Vector3f{0, 1, -fov.DownTan}, apex)
+	frustum.Planes[Frustum_Top] = newPlane(Vector3f{0, -1, -fov.UpTan}, apex)
+	frustum.Planes[Frustum_Near] = Plane{Normal: Vector3f{0, 0, -1}, D: -znear}
+	frustum.Planes[Frustum_Far] = Plane{Normal: Vector3f{0, 0, 1}, D: zfar}
+
+	// Nothing is behind a far plane at infinity.
+	if math.IsInf(float64(zfar), 1) {
+		frustum.Planes[Frustum_Far] = Plane{D: 1}
+	}
+
+	return frustum
+}
+
+// StereoFrustum returns a frustum that encloses the frustums of both eyes,
+// for culling the scene once for stereo rendering. headPose is the pose of the
+// point between the eyes, which are offset from it by the opposite of their
+// ViewAdjust. The FOV of each eye must have positive tangents.
+//
+// The frustum has the union of the FOVs of the eyes, and its apex is moved
+// back from the head until its sides enclose both eyes.
+func StereoFrustum(headPose Posef, eyeRenderDesc [Eye_Count]EyeRenderDesc, znear, zfar float32) Frustum {
+	fov := eyeRenderDesc[Eye_Left].Fov.Union(eyeRenderDesc[Eye_Right].Fov)
+
+	apexZ := float32(0)
+	nearZ, farZ := float32(math.Inf(-1)), float32(math.Inf(1))
+
+	for _, desc := range eyeRenderDesc {
+		eye := vectorScale(desc.ViewAdjust, -1)
+
+		// The side planes through the apex have the eye in front of them
+		// once the apex is far enough back.
+		apexZ = max(apexZ, eye.Z-eye.X/fov.LeftTan)
+		apexZ = max(apexZ, eye.Z+eye.X/fov.RightTan)
+		apexZ = max(apexZ, eye.Z-eye.Y/fov.DownTan)
+		apexZ = max(apexZ, eye.Z+eye.Y/fov.UpTan)
+
+		nearZ = max(nearZ, eye.Z-znear)
+		farZ = min(farZ, eye.Z-zfar)
+	}
+
+	frustum := frustumFromFov(fov, Vector3f{Z: apexZ}, -nearZ, -farZ)
+	return frustum.Transform(headPose)
+}
+
+// Transform returns the frustum moved from the frame of pose to the frame the
+// pose is given in.
+func (frustum Frustum) Transform(pose Posef) Frustum {
+	for i, plane := range frustum.Planes {
+		frustum.Planes[i] = plane.Transform(pose)
+	}
+
+	return frustum
+}
+
+// ContainsPoint returns whether p is inside the frustum.
+func (frustum Frustum) ContainsPoint(p Vector3f) bool {
+	for _, plane := range frustum.Planes {
+		if plane.Distance(p) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IntersectsSphere returns whether the sphere may be in view.
+func (frustum Frustum) IntersectsSphere(center Vector3f, radius float32) bool {
+	for _, plane := range frustum.Planes {
+		if plane.Distance(center) < -radius {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IntersectsAABB returns whether the axis-aligned box between min and max may
+// be in view.
+func (frustum Frustum) IntersectsAABB(min, max Vector3f) bool {
+	for _, plane := range frustum.Planes {
+		// The corner of the box that is the furthest in front of the plane.
+		corner := min
+		if plane.Normal.X > 0 {
+			corner.X = max.X
+		}
+		if plane.Normal.Y > 0 {
+			corner.Y = max.Y
+		}
+		if plane.Normal.Z > 0 {
+			corner.Z = max.Z
+		}
+
+		if plane.Distance(corner) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IntersectsOBB returns whether the box centered at pose may be in view. The
+// box extends halfExtents along each of the axes of pose.
+func (frustum Frustum) IntersectsOBB(pose Posef, halfExtents Vector3f) bool {
+	axes := [3]Vector3f{
+		pose.Orientation.Rotate(Vector3f{X: halfExtents.X}),
+		pose.Orientation.Rotate(Vector3f{Y: halfExtents.Y}),
+		pose.Orientation.Rotate(Vector3f{Z: halfExtents.Z}),
+	}
+
+	for _, plane := range frustum.Planes {
+		// The radius of the box, projected on the normal of the plane.
+		radius := float32(0)
+		for _, axis := range axes {
+			radius += float32(math.Abs(float64(vectorDot(plane.Normal, axis))))
+		}
+
+		if plane.Distance(pose.Position) < -radius {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,136 @@
+package ovr
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFrustumFromFov(t *testing.T) {
+	fov := FovPort{UpTan: 1, DownTan: 0.5, LeftTan: 1, RightTan: 2}
+
+	// The camera at (0, 0, 10) is turned to the left, towards -X.
+	pose := Posef{Orientation: QuatfFromAxisAngle(Vector3f{Y: 1}, math.Pi/2), Position: Vector3f{0, 0, 10}}
+	frustum := FrustumFromFov(fov, pose, 0.1, 100)
+
+	cases := []struct {
+		point  Vector3f
+		inside bool
+	}{
+		{Vector3f{-5, 0, 10}, true},
+		{Vector3f{-5, 4.9, 10}, true},
+		{Vector3f{-5, 5.1, 10}, false},
+		{Vector3f{-5, -2.4, 10}, true},
+		{Vector3f{-5, -2.6, 10}, false},
+		{Vector3f{-5, 0, 14.9}, true},
+		{Vector3f{-5, 0, 15.1}, false},
+		{Vector3f{-5, 0, 0.1}, true},
+		{Vector3f{-5, 0, -0.1}, false},
+		{Vector3f{-0.05, 0, 10}, false},
+		{Vector3f{-99, 0, 10}, true},
+		{Vector3f{-101, 0, 10}, false},
+		{Vector3f{5, 0, 10}, false},
+	}
+
+	for _, c := range cases {
+		if result := frustum.ContainsPoint(c.point); result != c.inside {
+			t.Errorf("Expected ContainsPoint(%v) to return %t", c.point, c.inside)
+		}
+	}
+
+	if frustum := FrustumFromFov(fov, pose, 0.1, float32(math.Inf(1))); !frustum.ContainsPoint(Vector3f{-1e6, 0, 10}) {
+		t.Error("Expected a frustum without far plane to contain far away points")
+	}
+}
+
+// The frustum holds the points the projection maps inside the clip volume.
+func TestFrustumMatchesProjection(t *testing.T) {
+	fov := FovPort{UpTan: 1.3292863, DownTan: 1.3292863, LeftTan: 1.0586576, RightTan: 1.092368}
+	frustum := FrustumFromFov(fov, Posef{Orientation: Quatf{W: 1}}, 0.5, 20)
+	projection := Projection(fov, 0.5, 20, Projection_RightHanded)
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Vector3f{random.Float32()*60 - 30, random.Float32()*60 - 30, -random.Float32() * 25}
+
+		w := -p.Z
+		clip := projection.Transform(p)
+		inside := clip.X >= -w && clip.X <= w && clip.Y >= -w && clip.Y <= w && clip.Z >= 0 && clip.Z <= w
+
+		if result := frustum.ContainsPoint(p); result != inside {
+			t.Errorf("Expected ContainsPoint(%v) to return %t, like the projection", p, inside)
+		}
+	}
+}
+
+func TestFrustumIntersections(t *testing.T) {
+	frustum := FrustumFromFov(FovPortFromDegrees(90, 90), Posef{Orientation: Quatf{W: 1}}, 0.1, 100)
+
+	// Along the right side, x = -z.
+	if !frustum.IntersectsSphere(Vector3f{10.5, 0, -10}, 1) || frustum.IntersectsSphere(Vector3f{12, 0, -10}, 1) {
+		t.Error("Expected a sphere to intersect only when it reaches the right side of the frustum")
+	}
+
+	if !frustum.IntersectsAABB(Vector3f{10.5, -1, -11}, Vector3f{12, 1, -9}) || frustum.IntersectsAABB(Vector3f{11.5, -1, -11}, Vector3f{12, 1, -9}) {
+		t.Error("Expected a box to intersect only when it reaches the right side of the frustum")
+	}
+
+	if !frustum.IntersectsAABB(Vector3f{-50, -50, -50}, Vector3f{50, 50, 50}) {
+		t.Error("Expected a box around the camera to intersect the frustum")
+	}
+
+	// A box of 2 by 2 meters, turned 45° around Y, reaches √2 meters to the
+	// left.
+	pose := Posef{Orientation: QuatfFromAxisAngle(Vector3f{Y: 1}, math.Pi/4), Position: Vector3f{12, 0, -10}}
+	if frustum.IntersectsOBB(pose, Vector3f{1, 1, 1}) {
+		t.Error("Expected the box at 12 meters not to intersect the frustum")
+	}
+
+	pose.Position.X = 11.2
+	if !frustum.IntersectsOBB(pose, Vector3f{1, 1, 1}) {
+		t.Error("Expected the turned box at 11.2 meters to intersect the frustum")
+	}
+}
+
+func TestStereoFrustum(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	eyeRenderDesc := [Eye_Count]EyeRenderDesc{
+		hmd.GetRenderDesc(Eye_Left, hmd.DefaultEyeFov[Eye_Left]),
+		hmd.GetRenderDesc(Eye_Right, hmd.DefaultEyeFov[Eye_Right]),
+	}
+
+	headPose := Posef{Orientation: QuatfFromAxisAngle(Vector3f{1, 1, 0}, 0.3), Position: Vector3f{1, 1.7, 2}}
+	stereo := StereoFrustum(headPose, eyeRenderDesc, 0.1, 50)
+
+	eyes := [Eye_Count]Frustum{}
+	for eye, desc := range eyeRenderDesc {
+		view := newEyeView(EyeType(eye), headPose, Posef{Orientation: Quatf{W: 1}}, desc, 0.1, 50, Projection_None)
+		eyes[eye] = FrustumFromFov(desc.Fov, view.WorldPose, 0.1, 50)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	inEyes := 0
+
+	for i := 0; i < 5000; i++ {
+		p := vectorAdd(headPose.Position, Vector3f{random.Float32()*100 - 50, random.Float32()*100 - 50, random.Float32()*100 - 50})
+		if !eyes[Eye_Left].ContainsPoint(p) && !eyes[Eye_Right].ContainsPoint(p) {
+			continue
+		}
+
+		inEyes++
+		if !stereo.ContainsPoint(p) {
+			t.Errorf("Expected the stereo frustum to contain %v, which one of the eyes sees", p)
+		}
+	}
+
+	if inEyes == 0 {
+		t.Error("Expected some of the points to be in view of the eyes")
+	}
+
+	// Just in front of the head, between the eyes, is out of view.
+	if ahead := headPose.Orientation.Rotate(Vector3f{Z: -0.05}); stereo.ContainsPoint(vectorAdd(headPose.Position, ahead)) {
+		t.Error("Expected the stereo frustum not to contain points before the near plane")
+	}
+}
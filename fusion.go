@@ -0,0 +1,285 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ****************************** [ Sensor fusion ] ***************************
+// ****************************************************************************
+
+// The algorithms an OrientationFilter can fuse the sensors with.
+const (
+	// Integrates the gyro, and turns the estimated up direction towards the
+	// one the accelerometer measures, a fraction TiltGain of the way per
+	// second.
+	Fusion_Complementary = 0
+
+	// Madgwick's gradient descent filter, with Beta as the gain.
+	Fusion_Madgwick = 1
+
+	// Mahony's explicit complementary filter, with Kp and Ki as the
+	// proportional and integral gains. The integral term learns the bias of
+	// the gyro around the horizontal axes.
+	Fusion_Mahony = 2
+)
+
+type FusionAlgorithm int
+
+// Standard gravity in m/s², which the accelerometer measures at rest.
+const standardGravity = 9.80665
+
+// The accelerometer is only trusted to show where up is when the size of its
+// reading is within this fraction of standard gravity. Beyond it, the head is
+// accelerating too much.
+const fusionGravityTolerance = 0.2
+
+// Below this horizontal strength in gauss, the magnetometer doesn't tell the
+// heading.
+const fusionMinHorizontalField = 0.05
+
+// The gains of an OrientationFilter. Only the ones of the chosen algorithm are
+// used.
+type FusionConfig struct {
+	Algorithm FusionAlgorithm
+
+	// The fraction of the tilt error Fusion_Complementary corrects per second.
+	TiltGain float32
+
+	// The gain of Fusion_Madgwick, in rad/s.
+	Beta float32
+
+	// The gains of Fusion_Mahony.
+	Kp float32
+	Ki float32
+
+	// Correct the drift in yaw with the magnetometer, like
+	// TrackingCap_MagYawCorrection does. The heading of the first reading is
+	// taken as the reference, and the fraction YawGain of the difference with
+	// it is corrected per second. Only the yaw is corrected, so magnetic
+	// disturbances don't tilt the orientation.
+	MagYawCorrection bool
+	YawGain          float32
+}
+
+// DefaultFusionConfig returns gains that suit the IMU of the DK1 and DK2 for
+// the given algorithm, without magnetometer correction.
+func DefaultFusionConfig(algorithm FusionAlgorithm) FusionConfig {
+	return FusionConfig{
+		Algorithm: algorithm,
+		TiltGain:  0.5,
+		Beta:      0.05,
+		Kp:        1.0,
+		Ki:        0.05,
+		YawGain:   0.2,
+	}
+}
+
+// An OrientationFilter estimates the orientation of the head from a stream of
+// SensorData, without libOVR, for example to replay the raw sensor data of a
+// recording. The estimated orientation starts out with the tilt the
+// accelerometer measures at the first reading. The sensors can't tell the
+// absolute yaw, so it is relative to the first reading.
+type OrientationFilter struct {
+	config FusionConfig
+
+	started     bool
+	orientation Quatf
+	lastTime    float32
+
+	// The integral of the tilt error of Fusion_Mahony.
+	integral Vector3f
+
+	hasReference     bool
+	referenceHeading float64
+}
+
+func NewOrientationFilter(config FusionConfig) *OrientationFilter {
+	return &OrientationFilter{config: config, orientation: Quatf{W: 1}}
+}
+
+// Orientation returns the current estimate.
+func (filter *OrientationFilter) Orientation() Quatf {
+	return filter.orientation
+}
+
+// Reset makes the filter start over with the next reading.
+func (filter *OrientationFilter) Reset() {
+	*filter = *NewOrientationFilter(filter.config)
+}
+
+// Update fuses a reading of the sensors, and returns the new estimate.
+// Readings have to come in the order of their TimeInSeconds.
+func (filter *OrientationFilter) Update(data SensorData) Quatf {
+	if !filter.started {
+		filter.started = true
+		filter.lastTime = data.TimeInSeconds
+		filter.orientation = levelOrientation(data.Accelerometer)
+		filter.correctYaw(data.Magnetometer, 0)
+
+		return filter.orientation
+	}
+
+	dt := data.TimeInSeconds - filter.lastTime
+	if dt <= 0 {
+		return filter.orientation
+	}
+	filter.lastTime = data.TimeInSeconds
+
+	// The accelerometer shows where up is when the head isn't accelerating.
+	up, upValid := Vector3f{}, false
+	if length := vectorLength(data.Accelerometer); math.Abs(float64(length)-standardGravity) < fusionGravityTolerance*standardGravity {
+		up, upValid = vectorScale(data.Accelerometer, 1/length), true
+	}
+
+	switch filter.config.Algorithm {
+	case Fusion_Madgwick:
+		filter.updateMadgwick(data.Gyro, up, upValid, dt)
+	case Fusion_Mahony:
+		filter.updateMahony(data.Gyro, up, upValid, dt)
+	default:
+		filter.updateComplementary(data.Gyro, up, upValid, dt)
+	}
+
+	filter.correctYaw(data.Magnetometer, dt)
+	return filter.orientation
+}
+
+// Returns the level orientation whose up direction is the one the
+// accelerometer measures.
+func levelOrientation(accelerometer Vector3f) Quatf {
+	if vectorLength(accelerometer) == 0 {
+		return Quatf{W: 1}
+	}
+
+	return quatfBetween(vectorNormalize(accelerometer), Vector3f{Y: 1})
+}
+
+// Returns the shortest rotation that takes the direction from to the direction
+// to. Both have to be normalized.
+func quatfBetween(from, to Vector3f) Quatf {
+	axis := vectorCross(from, to)
+	cos := float64(vectorDot(from, to))
+
+	if vectorLength(axis) < 1e-6 {
+		if cos > 0 {
+			return Quatf{W: 1}
+		}
+
+		// Half a turn, around any axis perpendicular to from.
+		axis = vectorCross(from, Vector3f{X: 1})
+		if vectorLength(axis) < 1e-6 {
+			axis = vectorCross(from, Vector3f{Z: 1})
+		}
+	}
+
+	return QuatfFromAxisAngle(axis, float32(math.Acos(math.Max(-1, math.Min(cos, 1)))))
+}
+
+// Turns the orientation by the rotation vector gyro·dt, in the frame of the
+// head.
+func (filter *OrientationFilter) integrateGyro(gyro Vector3f, dt float32) {
+	rotation := vectorScale(gyro, dt)
+	if angle := vectorLength(rotation); angle > 0 {
+		filter.orientation = filter.orientation.Mul(QuatfFromAxisAngle(rotation, angle)).Normalize()
+	}
+}
+
+func (filter *OrientationFilter) updateComplementary(gyro Vector3f, up Vector3f, upValid bool, dt float32) {
+	filter.integrateGyro(gyro, dt)
+	if !upValid {
+		return
+	}
+
+	// Turn the measured up direction part of the way to the actual one, in
+	// the frame of the world.
+	correction := quatfBetween(filter.orientation.Rotate(up), Vector3f{Y: 1})
+	axis, angle := correction.AxisAngle()
+	fraction := min(filter.config.TiltGain*dt, 1)
+
+	filter.orientation = QuatfFromAxisAngle(axis, angle*fraction).Mul(filter.orientation).Normalize()
+}
+
+func (filter *OrientationFilter) updateMahony(gyro Vector3f, up Vector3f, upValid bool, dt float32) {
+	if upValid {
+		// The error between the measured and the estimated up direction, in
+		// the frame of the head, pulls the gyro towards the measurement.
+		estimated := filter.orientation.InverseRotate(Vector3f{Y: 1})
+		err := vectorCross(up, estimated)
+
+		if filter.config.Ki > 0 {
+			filter.integral = vectorAdd(filter.integral, vectorScale(err, filter.config.Ki*dt))
+		}
+
+		gyro = vectorAdd(gyro, vectorAdd(vectorScale(err, filter.config.Kp), filter.integral))
+	} else {
+		gyro = vectorAdd(gyro, filter.integral)
+	}
+
+	filter.integrateGyro(gyro, dt)
+}
+
+func (filter *OrientationFilter) updateMadgwick(gyro Vector3f, up Vector3f, upValid bool, dt float32) {
+	q := filter.orientation
+
+	// dq/dt = q·ω/2
+	qDot := q.Mul(Quatf{gyro.X, gyro.Y, gyro.Z, 0})
+	qDot = Quatf{qDot.X / 2, qDot.Y / 2, qDot.Z / 2, qDot.W / 2}
+
+	if upValid {
+		// The gradient of |v(q) - up|², where v(q) is the up direction of the
+		// world in the frame of the head.
+		f1 := 2*(q.X*q.Y+q.W*q.Z) - up.X
+		f2 := 1 - 2*(q.X*q.X+q.Z*q.Z) - up.Y
+		f3 := 2*(q.Y*q.Z-q.W*q.X) - up.Z
+
+		gradient := Quatf{
+			X: 2*q.Y*f1 - 4*q.X*f2 - 2*q.W*f3,
+			Y: 2*q.X*f1 + 2*q.Z*f3,
+			Z: 2*q.W*f1 - 4*q.Z*f2 + 2*q.Y*f3,
+			W: 2*q.Z*f1 - 2*q.X*f3,
+		}
+
+		if length := gradient.Length(); length > 0 {
+			beta := filter.config.Beta / length
+			qDot = Quatf{
+				X: qDot.X - beta*gradient.X,
+				Y: qDot.Y - beta*gradient.Y,
+				Z: qDot.Z - beta*gradient.Z,
+				W: qDot.W - beta*gradient.W,
+			}
+		}
+	}
+
+	filter.orientation = Quatf{
+		X: q.X + qDot.X*dt,
+		Y: q.Y + qDot.Y*dt,
+		Z: q.Z + qDot.Z*dt,
+		W: q.W + qDot.W*dt,
+	}.Normalize()
+}
+
+// Turns the orientation around the vertical axis, towards the heading the
+// magnetometer had at the first reading.
+func (filter *OrientationFilter) correctYaw(magnetometer Vector3f, dt float32) {
+	if !filter.config.MagYawCorrection {
+		return
+	}
+
+	field := filter.orientation.Rotate(magnetometer)
+	if math.Hypot(float64(field.X), float64(field.Z)) < fusionMinHorizontalField {
+		return
+	}
+
+	heading := math.Atan2(float64(field.X), float64(field.Z))
+	if !filter.hasReference {
+		filter.hasReference = true
+		filter.referenceHeading = heading
+		return
+	}
+
+	// The heading turns with the yaw error, so undo part of it.
+	yawError := math.Remainder(heading-filter.referenceHeading, 2*math.Pi)
+	fraction := min(filter.config.YawGain*dt, 1)
+
+	correction := QuatfFromAxisAngle(Vector3f{Y: 1}, -float32(yawError)*fraction)
+	filter.orientation = correction.Mul(filter.orientation).Normalize()
+}
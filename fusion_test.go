@@ -0,0 +1,89 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+// Runs the filter over 20 seconds of head motion, with a biased gyro, and
+// returns the largest error in orientation and in tilt after the first 5
+// seconds.
+func runOrientationFilter(config FusionConfig, gyroBias Vector3f) (float64, float64) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	// The head only turns, so the accelerometer only measures gravity.
+	hmd.Simulator().SetMotionScript(&MotionScript{
+		Segments: []MotionSegment{
+			{Motion: YawSweep{Amplitude: 0.7, Period: 4}},
+			{Motion: Nod{Amplitude: 0.3, Period: 2.5}},
+		},
+	}, 0)
+
+	filter := NewOrientationFilter(config)
+	maxError, maxTiltError := 0.0, 0.0
+
+	for i := 0; i <= 20000; i++ {
+		state := hmd.GetTrackingState(float64(i) / 1000)
+
+		data := state.RawSensorData
+		data.Gyro = vectorAdd(data.Gyro, gyroBias)
+
+		estimated, actual := filter.Update(data), state.HeadPose.ThePose.Orientation
+		if i < 5000 {
+			continue
+		}
+
+		cos := math.Abs(float64(estimated.Dot(actual)))
+		maxError = math.Max(maxError, 2*math.Acos(math.Min(cos, 1)))
+
+		cos = float64(vectorDot(estimated.InverseRotate(Vector3f{Y: 1}), actual.InverseRotate(Vector3f{Y: 1})))
+		maxTiltError = math.Max(maxTiltError, math.Acos(math.Min(cos, 1)))
+	}
+
+	return maxError, maxTiltError
+}
+
+func TestOrientationFilter(t *testing.T) {
+	bias := Vector3f{0.01, 0.02, -0.01}
+
+	for _, algorithm := range []FusionAlgorithm{Fusion_Complementary, Fusion_Madgwick, Fusion_Mahony} {
+		config := DefaultFusionConfig(algorithm)
+
+		if maxError, _ := runOrientationFilter(config, Vector3f{}); maxError > 0.01 {
+			t.Errorf("Expected algorithm %d to follow the head within 0.01 radians, instead of %f", algorithm, maxError)
+		}
+
+		// The accelerometer corrects the tilt, but nothing corrects the yaw.
+		maxError, maxTiltError := runOrientationFilter(config, bias)
+		if maxTiltError > 0.05 || maxError < 0.2 {
+			t.Errorf("Expected algorithm %d to only drift in yaw with a biased gyro, instead of %f radians, %f in tilt", algorithm, maxError, maxTiltError)
+		}
+
+		config.MagYawCorrection = true
+		if maxError, _ := runOrientationFilter(config, bias); maxError > 0.15 {
+			t.Errorf("Expected the magnetometer to limit the drift of algorithm %d, instead of %f radians", algorithm, maxError)
+		}
+	}
+}
+
+func TestOrientationFilterStartsFromGravity(t *testing.T) {
+	filter := NewOrientationFilter(DefaultFusionConfig(Fusion_Mahony))
+
+	// The head is rolled to the left, so up is to its right.
+	accelerometer := Vector3f{standardGravity * 0.5, standardGravity * 0.8660254, 0}
+	orientation := filter.Update(SensorData{Accelerometer: accelerometer, TimeInSeconds: 1})
+
+	if up := orientation.Rotate(vectorNormalize(accelerometer)); !approxVector3f(Vector3f{Y: 1}, up, 1e-6) {
+		t.Errorf("Expected the measured gravity to point up, instead of %v", up)
+	}
+
+	if _, _, roll := orientation.YawPitchRoll(); !approxFloat(math.Pi/6, roll, 1e-6) {
+		t.Errorf("Expected a roll of 30° instead of %f", roll)
+	}
+
+	filter.Reset()
+	if orientation := filter.Update(SensorData{Accelerometer: Vector3f{Y: standardGravity}}); orientation != (Quatf{W: 1}) {
+		t.Errorf("Expected a level orientation after Reset(), instead of %v", orientation)
+	}
+}
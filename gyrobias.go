@@ -0,0 +1,334 @@
+package ovr
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// ****************************************************************************
+// ************************** [ Stillness detection ] *************************
+// ****************************************************************************
+
+// A StillnessDetector tells when the headset lies still, from a stream of
+// SensorData. It is still when the readings of the gyro and the accelerometer
+// have stayed close to their mean for StillTime seconds, the gyro measures
+// less than MaxAngularSpeed, and the accelerometer measures gravity alone.
+//
+// A headset turning slowly at a steady speed around the vertical axis looks
+// still too, so MaxAngularSpeed should stay close to the largest gyro bias.
+type StillnessDetector struct {
+	// How long the readings have to stay close to their mean, in seconds.
+	StillTime float32
+
+	// How far from their mean the readings of the gyro, in rad/s, and of the
+	// accelerometer, in m/s², may be. Raise them for noisy sensors.
+	GyroTolerance  float32
+	AccelTolerance float32
+
+	// The fastest the gyro may measure, in rad/s.
+	MaxAngularSpeed float32
+
+	started  bool
+	since    float32
+	count    int
+	gyroSum  Vector3f
+	accelSum Vector3f
+	still    bool
+}
+
+// NewStillnessDetector returns a detector with thresholds that suit the IMU
+// of the DK1 and DK2.
+func NewStillnessDetector() *StillnessDetector {
+	return &StillnessDetector{
+		StillTime:       1.0,
+		GyroTolerance:   0.03,
+		AccelTolerance:  0.2,
+		MaxAngularSpeed: 0.1,
+	}
+}
+
+// Update takes the next reading, and returns whether the headset is still.
+func (detector *StillnessDetector) Update(data SensorData) bool {
+	if !detector.started || data.TimeInSeconds < detector.since || detector.moved(data) {
+		// Start over from this reading.
+		detector.started = true
+		detector.since = data.TimeInSeconds
+		detector.count = 1
+		detector.gyroSum = data.Gyro
+		detector.accelSum = data.Accelerometer
+		detector.still = false
+
+		return false
+	}
+
+	detector.count++
+	detector.gyroSum = vectorAdd(detector.gyroSum, data.Gyro)
+	detector.accelSum = vectorAdd(detector.accelSum, data.Accelerometer)
+	detector.still = data.TimeInSeconds-detector.since >= detector.StillTime
+
+	return detector.still
+}
+
+// Returns whether the reading data is too far from the mean of the readings
+// since the headset was last seen moving.
+func (detector *StillnessDetector) moved(data SensorData) bool {
+	gyroMean := vectorScale(detector.gyroSum, 1/float32(detector.count))
+	accelMean := vectorScale(detector.accelSum, 1/float32(detector.count))
+	gravity := math.Abs(float64(vectorLength(data.Accelerometer)) - standardGravity)
+
+	return vectorLength(data.Gyro) > detector.MaxAngularSpeed ||
+		gravity > fusionGravityTolerance*standardGravity ||
+		vectorLength(vectorSub(data.Gyro, gyroMean)) > detector.GyroTolerance ||
+		vectorLength(vectorSub(data.Accelerometer, accelMean)) > detector.AccelTolerance
+}
+
+// Still returns whether the headset was still at the last reading.
+func (detector *StillnessDetector) Still() bool {
+	return detector.still
+}
+
+// Reset makes the detector start over with the next reading.
+func (detector *StillnessDetector) Reset() {
+	detector.started = false
+	detector.still = false
+}
+
+// ****************************************************************************
+// *************************** [ Gyro bias estimation ] ***********************
+// ****************************************************************************
+
+// The version of the gyro bias table file format.
+const gyroBiasTableVersion = 1
+
+// The bin size in °C of the tables that are given none.
+const gyroBiasDefaultBinSize = 1
+
+// The number of readings after which an entry of a GyroBiasTable stops
+// weighing them equally, and follows the newer ones. At 1000 Hz, this is a
+// minute of stillness.
+const gyroBiasMaxSamples = 60000
+
+// The bias of the gyro at a temperature.
+type GyroBiasEntry struct {
+	// The temperature in °C, the middle of the bin of the entry.
+	Temperature float32
+
+	// The bias in rad/s, the average reading of the gyro while still.
+	Bias Vector3f
+
+	// The number of readings averaged.
+	Samples int
+}
+
+// A GyroBiasTable holds the bias of the gyro at the temperatures it was
+// measured at, in bins of BinSize °C. The bias of a gyro changes with its
+// temperature, which warms up for minutes after the headset is plugged in.
+//
+// A table is saved as a JSON document like this:
+//
+//	{
+//	  "version": 1,
+//	  "binSize": 1,
+//	  "entries": [
+//	    {"temperature": 34, "bias": {"X": 0.004, "Y": -0.011, "Z": 0.002}, "samples": 60000},
+//	    {"temperature": 35, "bias": {"X": 0.005, "Y": -0.012, "Z": 0.002}, "samples": 18250}
+//	  ]
+//	}
+type GyroBiasTable struct {
+	BinSize float32
+
+	// The entries, by increasing temperature.
+	Entries []GyroBiasEntry
+}
+
+type gyroBiasTableJSON struct {
+	Version int                 `json:"version"`
+	BinSize float32             `json:"binSize"`
+	Entries []gyroBiasEntryJSON `json:"entries"`
+}
+
+type gyroBiasEntryJSON struct {
+	Temperature float32  `json:"temperature"`
+	Bias        Vector3f `json:"bias"`
+	Samples     int      `json:"samples"`
+}
+
+// NewGyroBiasTable returns an empty table with bins of binSize °C, or of 1 °C
+// if binSize isn't positive.
+func NewGyroBiasTable(binSize float32) *GyroBiasTable {
+	if !(binSize > 0) {
+		binSize = gyroBiasDefaultBinSize
+	}
+
+	return &GyroBiasTable{BinSize: binSize}
+}
+
+// Add averages a reading of the gyro, taken while still, into the entry of
+// the temperature. A table without a positive BinSize gets bins of 1 °C.
+func (table *GyroBiasTable) Add(temperature float32, gyro Vector3f) {
+	if !(table.BinSize > 0) {
+		table.BinSize = gyroBiasDefaultBinSize
+	}
+
+	binTemperature := float32(math.Round(float64(temperature/table.BinSize))) * table.BinSize
+
+	i := sort.Search(len(table.Entries), func(i int) bool {
+		return table.Entries[i].Temperature >= binTemperature
+	})
+
+	if i == len(table.Entries) || table.Entries[i].Temperature != binTemperature {
+		table.Entries = append(table.Entries, GyroBiasEntry{})
+		copy(table.Entries[i+1:], table.Entries[i:])
+		table.Entries[i] = GyroBiasEntry{Temperature: binTemperature}
+	}
+
+	entry := &table.Entries[i]
+	if entry.Samples < gyroBiasMaxSamples {
+		entry.Samples++
+	}
+	entry.Bias = vectorLerp(entry.Bias, gyro, 1/float32(entry.Samples))
+}
+
+// Bias returns the bias at the temperature, interpolated between the entries
+// around it, or the one of the closest entry beyond the range of the table.
+// It returns false if the table is empty.
+func (table *GyroBiasTable) Bias(temperature float32) (Vector3f, bool) {
+	entries := table.Entries
+	if len(entries) == 0 {
+		return Vector3f{}, false
+	}
+
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Temperature >= temperature
+	})
+
+	switch i {
+	case 0:
+		return entries[0].Bias, true
+	case len(entries):
+		return entries[len(entries)-1].Bias, true
+	}
+
+	a, b := entries[i-1], entries[i]
+	return vectorLerp(a.Bias, b.Bias, (temperature-a.Temperature)/(b.Temperature-a.Temperature)), true
+}
+
+func (table GyroBiasTable) MarshalJSON() ([]byte, error) {
+	doc := gyroBiasTableJSON{Version: gyroBiasTableVersion, BinSize: table.BinSize, Entries: []gyroBiasEntryJSON{}}
+	for _, entry := range table.Entries {
+		doc.Entries = append(doc.Entries, gyroBiasEntryJSON(entry))
+	}
+
+	return json.Marshal(doc)
+}
+
+func (table *GyroBiasTable) UnmarshalJSON(data []byte) error {
+	doc := gyroBiasTableJSON{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Version != gyroBiasTableVersion {
+		return fmt.Errorf("unsupported gyro bias table version %d", doc.Version)
+	}
+
+	if doc.BinSize <= 0 {
+		return fmt.Errorf("invalid gyro bias bin size %f", doc.BinSize)
+	}
+
+	*table = GyroBiasTable{BinSize: doc.BinSize}
+	for i, entry := range doc.Entries {
+		if i > 0 && entry.Temperature <= doc.Entries[i-1].Temperature {
+			return fmt.Errorf("entry %d: temperatures out of order", i)
+		}
+
+		table.Entries = append(table.Entries, GyroBiasEntry(entry))
+	}
+
+	return nil
+}
+
+// LoadGyroBiasTable reads a gyro bias table from a JSON file.
+func LoadGyroBiasTable(filename string) (*GyroBiasTable, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &GyroBiasTable{}
+	if err := json.Unmarshal(data, table); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// Save writes the gyro bias table to a JSON file.
+func (table *GyroBiasTable) Save(filename string) error {
+	data, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
+
+// A GyroBiasEstimator learns the bias of the gyro whenever the headset lies
+// still, and removes it from the readings. Without positional tracking or
+// magnetometer correction, the bias makes the yaw drift. The biases are kept
+// by temperature in a table, so a table saved from a previous run corrects
+// the gyro from the start.
+type GyroBiasEstimator struct {
+	Detector *StillnessDetector
+
+	table           *GyroBiasTable
+	bias            Vector3f
+	angularVelocity Vector3f
+}
+
+// NewGyroBiasEstimator returns an estimator that adds to table, or to a new
+// table with bins of 1 °C if table is nil.
+func NewGyroBiasEstimator(table *GyroBiasTable) *GyroBiasEstimator {
+	if table == nil {
+		table = NewGyroBiasTable(gyroBiasDefaultBinSize)
+	}
+
+	return &GyroBiasEstimator{Detector: NewStillnessDetector(), table: table}
+}
+
+// Update takes the next reading, and returns its angular velocity without the
+// bias of the gyro at its temperature.
+func (estimator *GyroBiasEstimator) Update(data SensorData) Vector3f {
+	if estimator.Detector.Update(data) {
+		estimator.table.Add(data.Temperature, data.Gyro)
+	}
+
+	estimator.bias, _ = estimator.table.Bias(data.Temperature)
+	estimator.angularVelocity = vectorSub(data.Gyro, estimator.bias)
+
+	return estimator.angularVelocity
+}
+
+// AngularVelocity returns the corrected angular velocity of the last reading,
+// in rad/s in the frame of the headset.
+func (estimator *GyroBiasEstimator) AngularVelocity() Vector3f {
+	return estimator.angularVelocity
+}
+
+// Bias returns the bias removed from the last reading.
+func (estimator *GyroBiasEstimator) Bias() Vector3f {
+	return estimator.bias
+}
+
+// Still returns whether the headset was still at the last reading.
+func (estimator *GyroBiasEstimator) Still() bool {
+	return estimator.Detector.Still()
+}
+
+// Table returns the table the estimator adds to, to save it.
+func (estimator *GyroBiasEstimator) Table() *GyroBiasTable {
+	return estimator.table
+}
@@ -0,0 +1,138 @@
+package ovr
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Returns the readings of a headset lying still for duration seconds from
+// start, at 1000 Hz, with a biased gyro.
+func stillSensorData(start, duration float32, temperature float32, bias Vector3f) []SensorData {
+	readings := []SensorData{}
+	for i := 0; i < int(duration*1000); i++ {
+		readings = append(readings, SensorData{
+			Accelerometer: Vector3f{0.3, standardGravity - 0.005, 0.1},
+			Gyro:          vectorAdd(bias, Vector3f{X: 0.002 * float32(i%3-1)}),
+			Magnetometer:  simMagneticField,
+			Temperature:   temperature,
+			TimeInSeconds: start + float32(i)/1000,
+		})
+	}
+
+	return readings
+}
+
+func TestStillnessDetector(t *testing.T) {
+	detector := NewStillnessDetector()
+
+	still := stillSensorData(0, 2, 35, Vector3f{0.01, -0.02, 0.005})
+	for i, data := range still {
+		if result := detector.Update(data); result != (i >= 1000) {
+			t.Fatalf("Expected the headset to be still after a second, instead of %t at %f", result, data.TimeInSeconds)
+		}
+	}
+
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.Simulator().SetMotionScript(testMotionScript(), 0)
+
+	for i := 0; i < 3000; i++ {
+		data := hmd.GetTrackingState(2 + float64(i)/1000).RawSensorData
+		if detector.Update(data) {
+			t.Fatalf("Expected a moving headset not to be still, at %f", data.TimeInSeconds)
+		}
+	}
+}
+
+func TestGyroBiasEstimator(t *testing.T) {
+	estimator := NewGyroBiasEstimator(nil)
+
+	biasCold, biasWarm := Vector3f{0.01, -0.02, 0.005}, Vector3f{0.02, -0.01, 0.005}
+
+	for _, data := range stillSensorData(0, 3, 30, biasCold) {
+		estimator.Update(data)
+	}
+
+	if !estimator.Still() || !approxVector3f(biasCold, estimator.Bias(), 1e-4) {
+		t.Errorf("Expected the bias %v while still, instead of %v", biasCold, estimator.Bias())
+	}
+
+	// The headset warms up, after being picked up.
+	estimator.Update(SensorData{Gyro: Vector3f{Y: 1}, Accelerometer: Vector3f{Y: standardGravity}, Temperature: 35, TimeInSeconds: 3})
+	for _, data := range stillSensorData(4, 3, 40, biasWarm) {
+		estimator.Update(data)
+	}
+
+	table := estimator.Table()
+	if len(table.Entries) != 2 || table.Entries[0].Temperature != 30 || table.Entries[1].Temperature != 40 {
+		t.Fatalf("Expected entries at 30 °C and 40 °C instead of %v", table.Entries)
+	}
+
+	if bias, ok := table.Bias(35); !ok || !approxVector3f(vectorLerp(biasCold, biasWarm, 0.5), bias, 1e-4) {
+		t.Errorf("Expected the bias at 35 °C to be interpolated, instead of %v", bias)
+	}
+
+	moving := SensorData{Gyro: vectorAdd(Vector3f{0.5, 0.2, -0.1}, biasCold), Accelerometer: Vector3f{Y: standardGravity}, Temperature: 30, TimeInSeconds: 8}
+	if result := estimator.Update(moving); !approxVector3f(Vector3f{0.5, 0.2, -0.1}, result, 1e-4) || result != estimator.AngularVelocity() {
+		t.Errorf("Expected the bias to be removed from the angular velocity, instead of %v", result)
+	}
+
+	// A saved table corrects the gyro from the first reading.
+	filename := filepath.Join(t.TempDir(), "gyrobias.json")
+	if err := table.Save(filename); err != nil {
+		t.Fatalf("Expected Save() to succeed, instead of '%s'", err)
+	}
+
+	loaded, err := LoadGyroBiasTable(filename)
+	if err != nil {
+		t.Fatalf("Expected LoadGyroBiasTable() to succeed, instead of '%s'", err)
+	}
+
+	if len(loaded.Entries) != 2 || loaded.Entries[1] != table.Entries[1] || loaded.BinSize != 1 {
+		t.Errorf("Expected the table to survive saving, instead of %v", loaded)
+	}
+
+	if result := NewGyroBiasEstimator(loaded).Update(moving); !approxVector3f(Vector3f{0.5, 0.2, -0.1}, result, 1e-4) {
+		t.Errorf("Expected the loaded table to correct the gyro, instead of %v", result)
+	}
+}
+
+func TestGyroBiasTableErrors(t *testing.T) {
+	for _, doc := range []string{
+		`{"version": 2, "binSize": 1, "entries": []}`,
+		`{"version": 1, "binSize": 0, "entries": []}`,
+		`{"version": 1, "binSize": 1, "entries": [{"temperature": 35}, {"temperature": 30}]}`,
+	} {
+		table := GyroBiasTable{}
+		if err := table.UnmarshalJSON([]byte(doc)); err == nil {
+			t.Errorf("Expected an error for %s", doc)
+		}
+	}
+
+	if _, ok := NewGyroBiasTable(1).Bias(35); ok {
+		t.Errorf("Expected an empty table to have no bias")
+	}
+}
+
+func TestGyroBiasTableBinSize(t *testing.T) {
+	if table := NewGyroBiasTable(0); table.BinSize != 1 {
+		t.Errorf("Expected bins of 1 °C for a bin size of 0, instead of %f", table.BinSize)
+	}
+
+	if table := NewGyroBiasTable(-2); table.BinSize != 1 {
+		t.Errorf("Expected bins of 1 °C for a negative bin size, instead of %f", table.BinSize)
+	}
+
+	table := &GyroBiasTable{}
+	table.Add(35.2, Vector3f{X: 0.01})
+	table.Add(35.4, Vector3f{X: 0.03})
+
+	if table.BinSize != 1 || len(table.Entries) != 1 || table.Entries[0].Temperature != 35 {
+		t.Fatalf("Expected a single entry at 35 °C, instead of %v", table)
+	}
+
+	if err := table.Save(filepath.Join(t.TempDir(), "gyrobias.json")); err != nil {
+		t.Errorf("Expected Save() to succeed, instead of '%s'", err)
+	}
+}
@@ -0,0 +1,177 @@
+//go:build libovr
+
+#include <stdio.h>
+#include <string.h>
+
+#include "libovr_loader.h"
+
+#if defined(_WIN32)
+#include <windows.h>
+#else
+#include <dlfcn.h>
+#endif
+
+// ****************************************************************************
+// ****************************** [ Library ] *********************************
+// ****************************************************************************
+
+static void* libovr_handle = NULL;
+static int libovr_loaded = 0;
+static char libovr_path[1024];
+static char libovr_error[1024];
+
+static void* libovr_open(const char* path) {
+#if defined(_WIN32)
+	if (path == NULL) {
+		return GetModuleHandleA(NULL);
+	}
+
+	HMODULE handle = LoadLibraryA(path);
+	if (handle == NULL) {
+		snprintf(libovr_error, sizeof(libovr_error), "%s: error code %lu", path, GetLastError());
+	}
+
+	return handle;
+#else
+	void* handle = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (handle == NULL) {
+		snprintf(libovr_error, sizeof(libovr_error), "%s", dlerror());
+	}
+
+	return handle;
+#endif
+}
+
+static void libovr_close(void* handle, int ownsHandle) {
+#if defined(_WIN32)
+	if (ownsHandle) {
+		FreeLibrary((HMODULE)handle);
+	}
+#else
+	(void)ownsHandle;
+	dlclose(handle);
+#endif
+}
+
+static void* libovr_symbol(const char* name) {
+#if defined(_WIN32)
+	return (void*)GetProcAddress((HMODULE)libovr_handle, name);
+#else
+	return dlsym(libovr_handle, name);
+#endif
+}
+
+// ****************************************************************************
+// ***************************** [ Functions ] ********************************
+// ****************************************************************************
+
+#define DECLARE_FUNC(ret, name, params, args) static ret (*p_##name) params = NULL;
+#define DECLARE_PROC(name, params, args) static void (*p_##name) params = NULL;
+
+LIBOVR_FUNCTIONS(DECLARE_FUNC, DECLARE_PROC)
+LIBOVR_WINDOWS_FUNCTIONS(DECLARE_FUNC, DECLARE_PROC)
+
+#define DEFINE_FUNC(ret, name, params, args) \
+	ret dl_##name params { \
+		if (p_##name == NULL) { \
+			ret zero; \
+			memset(&zero, 0, sizeof(zero)); \
+			return zero; \
+		} \
+		return p_##name args; \
+	}
+
+#define DEFINE_PROC(name, params, args) \
+	void dl_##name params { \
+		if (p_##name != NULL) { \
+			p_##name args; \
+		} \
+	}
+
+LIBOVR_FUNCTIONS(DEFINE_FUNC, DEFINE_PROC)
+LIBOVR_WINDOWS_FUNCTIONS(DEFINE_FUNC, DEFINE_PROC)
+
+// Looks up a function, and adds its name to the error message when the
+// library doesn't have it.
+#define RESOLVE_FUNC(ret, name, params, args) \
+	*(void**)(&p_##name) = libovr_symbol(#name); \
+	if (p_##name == NULL) { \
+		missing++; \
+		strncat(libovr_error, missing == 1 ? " " : ", ", sizeof(libovr_error) - strlen(libovr_error) - 1); \
+		strncat(libovr_error, #name, sizeof(libovr_error) - strlen(libovr_error) - 1); \
+	}
+
+#define RESOLVE_PROC(name, params, args) RESOLVE_FUNC(void, name, params, args)
+
+// Looks up a function the library may leave out.
+#define RESOLVE_OPTIONAL_FUNC(ret, name, params, args) \
+	*(void**)(&p_##name) = libovr_symbol(#name);
+
+#define RESOLVE_OPTIONAL_PROC(name, params, args) RESOLVE_OPTIONAL_FUNC(void, name, params, args)
+
+#if defined(_WIN32)
+#define RESOLVE_WINDOWS_FUNCTIONS() LIBOVR_WINDOWS_FUNCTIONS(RESOLVE_FUNC, RESOLVE_PROC)
+#else
+#define RESOLVE_WINDOWS_FUNCTIONS() LIBOVR_WINDOWS_FUNCTIONS(RESOLVE_OPTIONAL_FUNC, RESOLVE_OPTIONAL_PROC)
+#endif
+
+#define RESET_FUNC(ret, name, params, args) p_##name = NULL;
+#define RESET_PROC(name, params, args) p_##name = NULL;
+
+// Returns whether the library loaded from path is the one already loaded.
+static int libovr_same_path(const char* path) {
+	if (path == NULL || libovr_loaded == 2) {
+		return path == NULL && libovr_loaded == 2;
+	}
+
+	return strcmp(path, libovr_path) == 0;
+}
+
+int libovr_Load(const char* path) {
+	if (libovr_loaded) {
+		if (libovr_same_path(path)) {
+			return 1;
+		}
+
+		snprintf(libovr_error, sizeof(libovr_error), "already loaded from '%s'", libovr_path);
+		return 0;
+	}
+
+	libovr_handle = libovr_open(path);
+	if (libovr_handle == NULL) {
+		return 0;
+	}
+
+	int missing = 0;
+	snprintf(libovr_error, sizeof(libovr_error), "missing functions:");
+	LIBOVR_FUNCTIONS(RESOLVE_FUNC, RESOLVE_PROC)
+	RESOLVE_WINDOWS_FUNCTIONS()
+
+	if (missing > 0) {
+		LIBOVR_FUNCTIONS(RESET_FUNC, RESET_PROC)
+		LIBOVR_WINDOWS_FUNCTIONS(RESET_FUNC, RESET_PROC)
+		libovr_close(libovr_handle, path != NULL);
+		libovr_handle = NULL;
+		return 0;
+	}
+
+	libovr_loaded = path != NULL ? 1 : 2;
+	snprintf(libovr_path, sizeof(libovr_path), "%s", path != NULL ? path : "");
+	return 1;
+}
+
+void libovr_Unload(void) {
+	if (!libovr_loaded) {
+		return;
+	}
+
+	LIBOVR_FUNCTIONS(RESET_FUNC, RESET_PROC)
+	LIBOVR_WINDOWS_FUNCTIONS(RESET_FUNC, RESET_PROC)
+	libovr_close(libovr_handle, libovr_loaded == 1);
+	libovr_handle = NULL;
+	libovr_loaded = 0;
+}
+
+const char* libovr_Error(void) {
+	return libovr_error;
+}
@@ -0,0 +1,81 @@
+#ifndef LIBOVR_LOADER_H
+#define LIBOVR_LOADER_H
+
+#include <OVR_CAPI_GL.h>
+
+// Every function of the C-API the package uses. FUNC is given functions that
+// return a value, PROC the ones that don't. For each of them, the loader
+// defines a dl_ prefixed function that calls into the loaded library.
+#define LIBOVR_FUNCTIONS(FUNC, PROC) \
+	FUNC(ovrBool, ovr_InitializeRenderingShim, (void), ()) \
+	FUNC(ovrBool, ovr_Initialize, (void), ()) \
+	PROC(ovr_Shutdown, (void), ()) \
+	FUNC(const char*, ovr_GetVersionString, (void), ()) \
+	FUNC(int, ovrHmd_Detect, (void), ()) \
+	FUNC(ovrHmd, ovrHmd_Create, (int index), (index)) \
+	PROC(ovrHmd_Destroy, (ovrHmd hmd), (hmd)) \
+	FUNC(ovrHmd, ovrHmd_CreateDebug, (ovrHmdType type), (type)) \
+	FUNC(const char*, ovrHmd_GetLastError, (ovrHmd hmd), (hmd)) \
+	FUNC(unsigned int, ovrHmd_GetEnabledCaps, (ovrHmd hmd), (hmd)) \
+	PROC(ovrHmd_SetEnabledCaps, (ovrHmd hmd, unsigned int hmdCaps), (hmd, hmdCaps)) \
+	FUNC(ovrBool, ovrHmd_ConfigureTracking, (ovrHmd hmd, unsigned int supportedTrackingCaps, unsigned int requiredTrackingCaps), (hmd, supportedTrackingCaps, requiredTrackingCaps)) \
+	PROC(ovrHmd_RecenterPose, (ovrHmd hmd), (hmd)) \
+	FUNC(ovrTrackingState, ovrHmd_GetTrackingState, (ovrHmd hmd, double absTime), (hmd, absTime)) \
+	FUNC(ovrSizei, ovrHmd_GetFovTextureSize, (ovrHmd hmd, ovrEyeType eye, ovrFovPort fov, float pixelsPerDisplayPixel), (hmd, eye, fov, pixelsPerDisplayPixel)) \
+	FUNC(ovrBool, ovrHmd_ConfigureRendering, (ovrHmd hmd, const ovrRenderAPIConfig* apiConfig, unsigned int distortionCaps, const ovrFovPort eyeFovIn[2], ovrEyeRenderDesc eyeRenderDescOut[2]), (hmd, apiConfig, distortionCaps, eyeFovIn, eyeRenderDescOut)) \
+	FUNC(ovrFrameTiming, ovrHmd_BeginFrame, (ovrHmd hmd, unsigned int frameIndex), (hmd, frameIndex)) \
+	PROC(ovrHmd_EndFrame, (ovrHmd hmd, const ovrPosef renderPose[2], const ovrTexture eyeTexture[2]), (hmd, renderPose, eyeTexture)) \
+	FUNC(ovrPosef, ovrHmd_GetEyePose, (ovrHmd hmd, ovrEyeType eye), (hmd, eye)) \
+	FUNC(ovrEyeRenderDesc, ovrHmd_GetRenderDesc, (ovrHmd hmd, ovrEyeType eyeType, ovrFovPort fov), (hmd, eyeType, fov)) \
+	FUNC(ovrBool, ovrHmd_CreateDistortionMesh, (ovrHmd hmd, ovrEyeType eyeType, ovrFovPort fov, unsigned int distortionCaps, ovrDistortionMesh* meshData), (hmd, eyeType, fov, distortionCaps, meshData)) \
+	PROC(ovrHmd_DestroyDistortionMesh, (ovrDistortionMesh* meshData), (meshData)) \
+	PROC(ovrHmd_GetRenderScaleAndOffset, (ovrFovPort fov, ovrSizei textureSize, ovrRecti renderViewport, ovrVector2f uvScaleOffsetOut[2]), (fov, textureSize, renderViewport, uvScaleOffsetOut)) \
+	FUNC(ovrFrameTiming, ovrHmd_GetFrameTiming, (ovrHmd hmd, unsigned int frameIndex), (hmd, frameIndex)) \
+	FUNC(ovrFrameTiming, ovrHmd_BeginFrameTiming, (ovrHmd hmd, unsigned int frameIndex), (hmd, frameIndex)) \
+	PROC(ovrHmd_EndFrameTiming, (ovrHmd hmd), (hmd)) \
+	PROC(ovrHmd_ResetFrameTiming, (ovrHmd hmd, unsigned int frameIndex), (hmd, frameIndex)) \
+	PROC(ovrHmd_GetEyeTimewarpMatrices, (ovrHmd hmd, ovrEyeType eye, ovrPosef renderPose, ovrMatrix4f twmOut[2]), (hmd, eye, renderPose, twmOut)) \
+	FUNC(ovrMatrix4f, ovrMatrix4f_Projection, (ovrFovPort fov, float znear, float zfar, ovrBool rightHanded), (fov, znear, zfar, rightHanded)) \
+	FUNC(ovrMatrix4f, ovrMatrix4f_OrthoSubProjection, (ovrMatrix4f projection, ovrVector2f orthoScale, float orthoDistance, float eyeViewAdjustX), (projection, orthoScale, orthoDistance, eyeViewAdjustX)) \
+	FUNC(double, ovr_GetTimeInSeconds, (void), ()) \
+	FUNC(double, ovr_WaitTillTime, (double absTime), (absTime)) \
+	FUNC(ovrBool, ovrHmd_ProcessLatencyTest, (ovrHmd hmd, unsigned char rgbColorOut[3]), (hmd, rgbColorOut)) \
+	FUNC(const char*, ovrHmd_GetLatencyTestResult, (ovrHmd hmd), (hmd)) \
+	PROC(ovrHmd_GetHSWDisplayState, (ovrHmd hmd, ovrHSWDisplayState* hasWarningState), (hmd, hasWarningState)) \
+	FUNC(ovrBool, ovrHmd_DismissHSWDisplay, (ovrHmd hmd), (hmd)) \
+	FUNC(ovrBool, ovrHmd_GetBool, (ovrHmd hmd, const char* propertyName, ovrBool defaultVal), (hmd, propertyName, defaultVal)) \
+	FUNC(ovrBool, ovrHmd_SetBool, (ovrHmd hmd, const char* propertyName, ovrBool value), (hmd, propertyName, value)) \
+	FUNC(int, ovrHmd_GetInt, (ovrHmd hmd, const char* propertyName, int defaultVal), (hmd, propertyName, defaultVal)) \
+	FUNC(ovrBool, ovrHmd_SetInt, (ovrHmd hmd, const char* propertyName, int value), (hmd, propertyName, value)) \
+	FUNC(float, ovrHmd_GetFloat, (ovrHmd hmd, const char* propertyName, float defaultVal), (hmd, propertyName, defaultVal)) \
+	FUNC(ovrBool, ovrHmd_SetFloat, (ovrHmd hmd, const char* propertyName, float value), (hmd, propertyName, value)) \
+	FUNC(unsigned int, ovrHmd_GetFloatArray, (ovrHmd hmd, const char* propertyName, float values[], unsigned int arraySize), (hmd, propertyName, values, arraySize)) \
+	FUNC(ovrBool, ovrHmd_SetFloatArray, (ovrHmd hmd, const char* propertyName, float values[], unsigned int arraySize), (hmd, propertyName, values, arraySize)) \
+	FUNC(const char*, ovrHmd_GetString, (ovrHmd hmd, const char* propertyName, const char* defaultVal), (hmd, propertyName, defaultVal)) \
+	FUNC(ovrBool, ovrHmd_SetString, (ovrHmd hmd, const char* propertyName, const char* value), (hmd, propertyName, value))
+
+// The functions only the Windows builds of libOVR export. Elsewhere they are
+// loaded if the library has them, and their dl_ functions return zero values
+// if it doesn't.
+#define LIBOVR_WINDOWS_FUNCTIONS(FUNC, PROC) \
+	FUNC(ovrBool, ovrHmd_AttachToWindow, (ovrHmd hmd, void* window, const ovrRecti* destMirrorRect, const ovrRecti* sourceRenderTargetRect), (hmd, window, destMirrorRect, sourceRenderTargetRect))
+
+#define LIBOVR_DECLARE_FUNC(ret, name, params, args) ret dl_##name params;
+#define LIBOVR_DECLARE_PROC(name, params, args) void dl_##name params;
+
+LIBOVR_FUNCTIONS(LIBOVR_DECLARE_FUNC, LIBOVR_DECLARE_PROC)
+LIBOVR_WINDOWS_FUNCTIONS(LIBOVR_DECLARE_FUNC, LIBOVR_DECLARE_PROC)
+
+// Loads libOVR from path, or looks the functions up in the program itself
+// when path is NULL. Returns 1 on success, or 0 after which libovr_Error()
+// describes what went wrong. Loading an already loaded library succeeds, but
+// loading another one fails until libovr_Unload() is called.
+int libovr_Load(const char* path);
+
+// Unloads the library. The dl_ functions return zero values until it is
+// loaded again.
+void libovr_Unload(void);
+
+const char* libovr_Error(void);
+
+#endif
@@ -0,0 +1,355 @@
+package ovr
+
+import (
+	"errors"
+	"math"
+)
+
+// ****************************************************************************
+// ************************ [ Magnetometer calibration ] **********************
+// ****************************************************************************
+
+// The fewest samples MagCalibrator.Calibrate() fits an ellipsoid to. The fit
+// has 9 unknowns.
+const magCalibrationMinSamples = 20
+
+// Samples closer than this in gauss to one already collected are dropped, so
+// holding the headset still doesn't weigh its direction more.
+const magCalibrationMinSpacing = 0.01
+
+// The most samples a MagCalibrator keeps. Later ones are dropped.
+const magCalibrationMaxSamples = 2000
+
+// The number of directions the coverage of a calibration is counted in: each
+// face of a cube around the origin, split in four.
+const magCalibrationCoverageBins = 24
+
+// A MagCalibration undoes the distortions of the magnetic field around the
+// magnetometer. Hard-iron distortions, from magnetized parts of the headset,
+// add an Offset to the readings. Soft-iron distortions, from metal that bends
+// the field, and differences between the axes of the sensor, turn the sphere
+// the readings lie on into an ellipsoid, which SoftIron turns back into a
+// sphere.
+type MagCalibration struct {
+	// The hard-iron offset in gauss, the center of the ellipsoid.
+	Offset Vector3f
+
+	// The soft-iron correction. Only the top-left 3x3 part is used. It keeps
+	// the average radius, so the corrected readings are still in gauss.
+	SoftIron Matrix4f
+
+	// The strength of the field in gauss, the radius of the corrected sphere.
+	FieldStrength float32
+
+	// The root mean square of the distance of the corrected samples from the
+	// sphere, relative to FieldStrength. Above a few percent, the samples were
+	// disturbed, or the headset moved near metal.
+	FitError float32
+
+	// The fraction of the directions around the headset the samples cover,
+	// from 0 to 1. Below about 0.75, the fit is poorly constrained, and the
+	// user should rotate the headset more.
+	Coverage float32
+}
+
+// Correct returns the raw magnetometer reading v without the distortions.
+func (cal MagCalibration) Correct(v Vector3f) Vector3f {
+	return cal.SoftIron.Transform(vectorSub(v, cal.Offset))
+}
+
+// Apply returns the reading data with the magnetometer corrected, to pass to
+// an OrientationFilter.
+func (cal MagCalibration) Apply(data SensorData) SensorData {
+	data.Magnetometer = cal.Correct(data.Magnetometer)
+	return data
+}
+
+// A MagCalibrator collects magnetometer readings while the user turns the
+// headset around in every direction, and fits a MagCalibration to them.
+type MagCalibrator struct {
+	samples []Vector3f
+}
+
+func NewMagCalibrator() *MagCalibrator {
+	return &MagCalibrator{}
+}
+
+// Add collects the magnetometer reading of data. It returns false if the
+// reading was dropped, because it is too close to one already collected, or
+// there are enough samples.
+func (calibrator *MagCalibrator) Add(data SensorData) bool {
+	if len(calibrator.samples) >= magCalibrationMaxSamples {
+		return false
+	}
+
+	for _, sample := range calibrator.samples {
+		if vectorLength(vectorSub(sample, data.Magnetometer)) < magCalibrationMinSpacing {
+			return false
+		}
+	}
+
+	calibrator.samples = append(calibrator.samples, data.Magnetometer)
+	return true
+}
+
+// Samples returns the number of samples collected.
+func (calibrator *MagCalibrator) Samples() int {
+	return len(calibrator.samples)
+}
+
+// Reset drops the samples collected.
+func (calibrator *MagCalibrator) Reset() {
+	calibrator.samples = nil
+}
+
+// Calibrate fits an ellipsoid to the samples collected, and returns the
+// calibration that turns it into a sphere. It fails if there are too few
+// samples, or they don't surround the center enough to tell its shape. A
+// calibration with a low Coverage or a high FitError should be collected
+// again.
+func (calibrator *MagCalibrator) Calibrate() (MagCalibration, error) {
+	if len(calibrator.samples) < magCalibrationMinSamples {
+		return MagCalibration{}, errors.New("too few magnetometer samples to calibrate")
+	}
+
+	// Fit in coordinates centered on the mean and scaled to about one, so the
+	// squares and the linear terms are of the same size.
+	mean := [3]float64{}
+	for _, sample := range calibrator.samples {
+		mean[0] += float64(sample.X)
+		mean[1] += float64(sample.Y)
+		mean[2] += float64(sample.Z)
+	}
+	for i := range mean {
+		mean[i] /= float64(len(calibrator.samples))
+	}
+
+	points := make([][3]float64, len(calibrator.samples))
+	scale := 0.0
+	for i, sample := range calibrator.samples {
+		points[i] = [3]float64{float64(sample.X) - mean[0], float64(sample.Y) - mean[1], float64(sample.Z) - mean[2]}
+		scale += points[i][0]*points[i][0] + points[i][1]*points[i][1] + points[i][2]*points[i][2]
+	}
+	scale = math.Sqrt(scale / float64(len(points)))
+	if scale == 0 {
+		return MagCalibration{}, errors.New("the magnetometer samples don't cover enough directions")
+	}
+
+	// Least squares fit of ax² + by² + cz² + 2dxy + 2exz + 2fyz + 2gx + 2hy +
+	// 2iz = 1, through its normal equations.
+	normal := make([][]float64, 9)
+	for i := range normal {
+		normal[i] = make([]float64, 9)
+	}
+	rhs := make([]float64, 9)
+
+	for i := range points {
+		x, y, z := points[i][0]/scale, points[i][1]/scale, points[i][2]/scale
+		row := [9]float64{x * x, y * y, z * z, 2 * x * y, 2 * x * z, 2 * y * z, 2 * x, 2 * y, 2 * z}
+
+		for j := range row {
+			for k := range row {
+				normal[j][k] += row[j] * row[k]
+			}
+			rhs[j] += row[j]
+		}
+	}
+
+	p, ok := solveLinear(normal, rhs)
+	if !ok {
+		return MagCalibration{}, errors.New("the magnetometer samples don't cover enough directions")
+	}
+
+	// x^T·A·x + 2b·x = 1 is the ellipsoid (x-c)^T·A·(x-c) = k, centered on
+	// c = -A⁻¹·b, with k = 1 - b·c.
+	a := [3][3]float64{
+		{p[0], p[3], p[4]},
+		{p[3], p[1], p[5]},
+		{p[4], p[5], p[2]},
+	}
+	b := [3]float64{p[6], p[7], p[8]}
+
+	rows := a
+	center, ok := solveLinear([][]float64{rows[0][:], rows[1][:], rows[2][:]}, []float64{-b[0], -b[1], -b[2]})
+	if !ok {
+		return MagCalibration{}, errors.New("the magnetometer samples don't fit an ellipsoid")
+	}
+	k := 1 - (b[0]*center[0] + b[1]*center[1] + b[2]*center[2])
+
+	shape := [3][3]float64{}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			shape[i][j] = a[i][j] / k
+		}
+	}
+
+	// The radii of the ellipsoid are 1/√λ for the eigenvalues λ of its shape,
+	// and √shape turns it into the unit sphere.
+	values, vectors := symmetricEigen3(shape)
+	product := 1.0
+	for _, value := range values {
+		if value <= 0 {
+			return MagCalibration{}, errors.New("the magnetometer samples don't fit an ellipsoid")
+		}
+		product *= value
+	}
+	radius := math.Pow(product, -1.0/6)
+
+	cal := MagCalibration{
+		Offset: Vector3f{
+			float32(mean[0] + center[0]*scale),
+			float32(mean[1] + center[1]*scale),
+			float32(mean[2] + center[2]*scale),
+		},
+		SoftIron:      Matrix4f_Identity(),
+		FieldStrength: float32(radius * scale),
+	}
+
+	// The fit was scaled, so √shape/scale undoes the scaling, and ·radius·scale
+	// keeps the average radius.
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			sum := 0.0
+			for n := 0; n < 3; n++ {
+				sum += vectors[i][n] * math.Sqrt(values[n]) * vectors[j][n]
+			}
+			cal.SoftIron.M[i][j] = float32(sum * radius)
+		}
+	}
+
+	covered := [magCalibrationCoverageBins]bool{}
+	squaredError := 0.0
+	for _, sample := range calibrator.samples {
+		corrected := cal.Correct(sample)
+
+		relative := float64(vectorLength(corrected)/cal.FieldStrength) - 1
+		squaredError += relative * relative
+		covered[coverageBin(corrected)] = true
+	}
+	cal.FitError = float32(math.Sqrt(squaredError / float64(len(calibrator.samples))))
+
+	count := 0
+	for _, c := range covered {
+		if c {
+			count++
+		}
+	}
+	cal.Coverage = float32(count) / magCalibrationCoverageBins
+
+	return cal, nil
+}
+
+// Returns the bin of the direction v: the face of the cube around the origin
+// v points at, and the quarter of that face.
+func coverageBin(v Vector3f) int {
+	c := [3]float32{v.X, v.Y, v.Z}
+
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(float64(c[i])) > math.Abs(float64(c[axis])) {
+			axis = i
+		}
+	}
+
+	bin := axis * 8
+	if c[axis] < 0 {
+		bin += 4
+	}
+	if c[(axis+1)%3] < 0 {
+		bin += 2
+	}
+	if c[(axis+2)%3] < 0 {
+		bin++
+	}
+
+	return bin
+}
+
+// Solves a·x = b by Gaussian elimination with partial pivoting, overwriting a
+// and b. It returns false if a is singular, or too close to singular to tell x.
+func solveLinear(a [][]float64, b []float64) ([]float64, bool) {
+	n := len(b)
+
+	largest := 0.0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			largest = math.Max(largest, math.Abs(a[i][j]))
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+
+		if math.Abs(a[pivot][col]) <= 1e-10*largest {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			f := a[row][col] / a[col][col]
+			for j := col; j < n; j++ {
+				a[row][j] -= f * a[col][j]
+			}
+			b[row] -= f * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for j := row + 1; j < n; j++ {
+			sum -= a[row][j] * x[j]
+		}
+		x[row] = sum / a[row][row]
+	}
+
+	return x, true
+}
+
+// Returns the eigenvalues of the symmetric matrix a, and the eigenvectors in
+// the matching columns, with Jacobi rotations.
+func symmetricEigen3(a [3][3]float64) ([3]float64, [3][3]float64) {
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+	for sweep := 0; sweep < 50; sweep++ {
+		off := a[0][1]*a[0][1] + a[0][2]*a[0][2] + a[1][2]*a[1][2]
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+
+				// The rotation in the p-q plane that zeroes a[p][q].
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < 3; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < 3; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := 0; k < 3; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	return [3]float64{a[0][0], a[1][1], a[2][2]}, v
+}
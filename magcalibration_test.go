@@ -0,0 +1,97 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+// Returns the readings of a magnetometer with the distortions softIron and
+// offset, while the headset is turned in every direction.
+func distortedMagnetometer(softIron Matrix4f, offset Vector3f) (readings, actual []Vector3f) {
+	for yaw := 0; yaw < 360; yaw += 20 {
+		for pitch := -80; pitch <= 80; pitch += 20 {
+			for roll := 0; roll < 360; roll += 90 {
+				q := QuatfFromEulerAngles(Axis_Y, Axis_X, Axis_Z,
+					float32(yaw)*math.Pi/180, float32(pitch)*math.Pi/180, float32(roll)*math.Pi/180, Rotate_CCW, Handed_R)
+
+				field := q.InverseRotate(simMagneticField)
+				readings = append(readings, vectorAdd(softIron.Transform(field), offset))
+				actual = append(actual, field)
+			}
+		}
+	}
+
+	return readings, actual
+}
+
+func TestMagCalibrator(t *testing.T) {
+	softIron := Matrix4f_Identity()
+	softIron.M = [4][4]float32{
+		{1.15, 0.05, -0.02, 0},
+		{0.05, 0.9, 0.03, 0},
+		{-0.02, 0.03, 1.05, 0},
+		{0, 0, 0, 1},
+	}
+	offset := Vector3f{0.12, -0.35, 0.08}
+
+	readings, actual := distortedMagnetometer(softIron, offset)
+
+	calibrator := NewMagCalibrator()
+	for _, reading := range readings {
+		calibrator.Add(SensorData{Magnetometer: reading})
+	}
+
+	cal, err := calibrator.Calibrate()
+	if err != nil {
+		t.Fatalf("Expected a calibration instead of %s", err)
+	}
+
+	if !approxVector3f(offset, cal.Offset, 1e-4) {
+		t.Errorf("Expected the hard-iron offset %v instead of %v", offset, cal.Offset)
+	}
+
+	if cal.FitError > 1e-4 || cal.Coverage != 1 {
+		t.Errorf("Expected a perfect fit with full coverage, instead of an error of %f and a coverage of %f", cal.FitError, cal.Coverage)
+	}
+
+	// A symmetric soft-iron distortion is undone up to a scale, so the
+	// corrected readings point like the actual field.
+	for i, reading := range readings {
+		corrected := cal.Apply(SensorData{Magnetometer: reading}).Magnetometer
+
+		if length := vectorLength(corrected); !approxFloat(cal.FieldStrength, length, 1e-4) {
+			t.Errorf("Expected the corrected reading %v to be %f gauss long, instead of %f", corrected, cal.FieldStrength, length)
+		}
+
+		if exp := vectorNormalize(actual[i]); !approxVector3f(exp, vectorNormalize(corrected), 1e-4) {
+			t.Errorf("Expected the corrected reading to point at %v instead of %v", exp, vectorNormalize(corrected))
+		}
+	}
+}
+
+func TestMagCalibratorCoverage(t *testing.T) {
+	calibrator := NewMagCalibrator()
+
+	if !calibrator.Add(SensorData{Magnetometer: simMagneticField}) || calibrator.Add(SensorData{Magnetometer: simMagneticField}) {
+		t.Errorf("Expected a repeated reading to be dropped")
+	}
+
+	if _, err := calibrator.Calibrate(); err == nil {
+		t.Errorf("Expected a single sample to be too few to calibrate")
+	}
+
+	// Turning the headset only in yaw leaves the vertical shape unknown.
+	calibrator.Reset()
+	for yaw := 0; yaw < 360; yaw += 5 {
+		q := QuatfFromAxisAngle(Vector3f{Y: 1}, float32(yaw)*math.Pi/180)
+		calibrator.Add(SensorData{Magnetometer: q.InverseRotate(simMagneticField)})
+	}
+
+	if calibrator.Samples() != 72 {
+		t.Errorf("Expected 72 samples instead of %d", calibrator.Samples())
+	}
+
+	if cal, err := calibrator.Calibrate(); err == nil {
+		t.Errorf("Expected samples in a single plane to fail, instead of %v", cal)
+	}
+}
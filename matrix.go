@@ -0,0 +1,209 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ********************************* [ Matrix4f ] *****************************
+// ****************************************************************************
+
+// Matrix4f_Identity returns the identity matrix.
+func Matrix4f_Identity() Matrix4f {
+	return Matrix4f{M: [4][4]float32{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}}
+}
+
+// Matrix4f_Translation returns the matrix that moves points by v.
+func Matrix4f_Translation(v Vector3f) Matrix4f {
+	m := Matrix4f_Identity()
+	m.M[0][3] = v.X
+	m.M[1][3] = v.Y
+	m.M[2][3] = v.Z
+
+	return m
+}
+
+// Matrix4f_Rotation returns the matrix that rotates like q, which should be
+// normalized.
+func Matrix4f_Rotation(q Quatf) Matrix4f {
+	ww, xx, yy, zz := q.W*q.W, q.X*q.X, q.Y*q.Y, q.Z*q.Z
+
+	return Matrix4f{M: [4][4]float32{
+		{ww + xx - yy - zz, 2 * (q.X*q.Y - q.W*q.Z), 2 * (q.X*q.Z + q.W*q.Y), 0},
+		{2 * (q.X*q.Y + q.W*q.Z), ww - xx + yy - zz, 2 * (q.Y*q.Z - q.W*q.X), 0},
+		{2 * (q.X*q.Z - q.W*q.Y), 2 * (q.Y*q.Z + q.W*q.X), ww - xx - yy + zz, 0},
+		{0, 0, 0, 1},
+	}}
+}
+
+// Matrix4f_FromPose returns the matrix that takes points from the frame of the
+// pose to the frame the pose is given in: it rotates by the orientation, then
+// moves by the position. Its inverse is the view matrix of a camera at the
+// pose.
+func Matrix4f_FromPose(pose Posef) Matrix4f {
+	m := Matrix4f_Rotation(pose.Orientation)
+	m.M[0][3] = pose.Position.X
+	m.M[1][3] = pose.Position.Y
+	m.M[2][3] = pose.Position.Z
+
+	return m
+}
+
+// Matrix4f_LookAtRH returns the view matrix of a camera at eye looking at
+// target, in a right-handed coordinate system where the camera looks down -Z.
+func Matrix4f_LookAtRH(eye, target, up Vector3f) Matrix4f {
+	return lookAt(eye, vectorSub(eye, target), up)
+}
+
+// Matrix4f_LookAtLH returns the view matrix of a camera at eye looking at
+// target, in a left-handed coordinate system where the camera looks down +Z.
+func Matrix4f_LookAtLH(eye, target, up Vector3f) Matrix4f {
+	return lookAt(eye, vectorSub(target, eye), up)
+}
+
+// Returns the view matrix of a camera at eye, whose Z axis points along back.
+func lookAt(eye, back, up Vector3f) Matrix4f {
+	z := vectorNormalize(back)
+	x := vectorNormalize(vectorCross(up, z))
+	y := vectorCross(z, x)
+
+	return Matrix4f{M: [4][4]float32{
+		{x.X, x.Y, x.Z, -vectorDot(x, eye)},
+		{y.X, y.Y, y.Z, -vectorDot(y, eye)},
+		{z.X, z.Y, z.Z, -vectorDot(z, eye)},
+		{0, 0, 0, 1},
+	}}
+}
+
+// Mul returns the matrix that applies n, then m.
+func (m Matrix4f) Mul(n Matrix4f) Matrix4f {
+	result := Matrix4f{}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				result.M[i][j] += m.M[i][k] * n.M[k][j]
+			}
+		}
+	}
+
+	return result
+}
+
+// Transform returns the point v transformed by m. The bottom row of m is
+// ignored, so there is no perspective division.
+func (m Matrix4f) Transform(v Vector3f) Vector3f {
+	return Vector3f{
+		m.M[0][0]*v.X + m.M[0][1]*v.Y + m.M[0][2]*v.Z + m.M[0][3],
+		m.M[1][0]*v.X + m.M[1][1]*v.Y + m.M[1][2]*v.Z + m.M[1][3],
+		m.M[2][0]*v.X + m.M[2][1]*v.Y + m.M[2][2]*v.Z + m.M[2][3],
+	}
+}
+
+func (m Matrix4f) Transpose() Matrix4f {
+	result := Matrix4f{}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			result.M[i][j] = m.M[j][i]
+		}
+	}
+
+	return result
+}
+
+// Returns the determinants of the 2x2 submatrices in the top two rows and in
+// the bottom two rows, which the determinant and the inverse are built from.
+func (m Matrix4f) minors() (s, c [6]float64) {
+	a := func(i, j int) float64 { return float64(m.M[i][j]) }
+
+	s = [6]float64{
+		a(0, 0)*a(1, 1) - a(1, 0)*a(0, 1),
+		a(0, 0)*a(1, 2) - a(1, 0)*a(0, 2),
+		a(0, 0)*a(1, 3) - a(1, 0)*a(0, 3),
+		a(0, 1)*a(1, 2) - a(1, 1)*a(0, 2),
+		a(0, 1)*a(1, 3) - a(1, 1)*a(0, 3),
+		a(0, 2)*a(1, 3) - a(1, 2)*a(0, 3),
+	}
+
+	c = [6]float64{
+		a(2, 0)*a(3, 1) - a(3, 0)*a(2, 1),
+		a(2, 0)*a(3, 2) - a(3, 0)*a(2, 2),
+		a(2, 0)*a(3, 3) - a(3, 0)*a(2, 3),
+		a(2, 1)*a(3, 2) - a(3, 1)*a(2, 2),
+		a(2, 1)*a(3, 3) - a(3, 1)*a(2, 3),
+		a(2, 2)*a(3, 3) - a(3, 2)*a(2, 3),
+	}
+
+	return s, c
+}
+
+func (m Matrix4f) Determinant() float32 {
+	s, c := m.minors()
+	return float32(s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0])
+}
+
+// Inverse returns the inverse of m, or m itself if it is singular.
+func (m Matrix4f) Inverse() Matrix4f {
+	s, c := m.minors()
+
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if det == 0 || math.IsNaN(det) {
+		return m
+	}
+
+	a := func(i, j int) float64 { return float64(m.M[i][j]) }
+	inv := [4][4]float64{
+		{
+			a(1, 1)*c[5] - a(1, 2)*c[4] + a(1, 3)*c[3],
+			-a(0, 1)*c[5] + a(0, 2)*c[4] - a(0, 3)*c[3],
+			a(3, 1)*s[5] - a(3, 2)*s[4] + a(3, 3)*s[3],
+			-a(2, 1)*s[5] + a(2, 2)*s[4] - a(2, 3)*s[3],
+		},
+		{
+			-a(1, 0)*c[5] + a(1, 2)*c[2] - a(1, 3)*c[1],
+			a(0, 0)*c[5] - a(0, 2)*c[2] + a(0, 3)*c[1],
+			-a(3, 0)*s[5] + a(3, 2)*s[2] - a(3, 3)*s[1],
+			a(2, 0)*s[5] - a(2, 2)*s[2] + a(2, 3)*s[1],
+		},
+		{
+			a(1, 0)*c[4] - a(1, 1)*c[2] + a(1, 3)*c[0],
+			-a(0, 0)*c[4] + a(0, 1)*c[2] - a(0, 3)*c[0],
+			a(3, 0)*s[4] - a(3, 1)*s[2] + a(3, 3)*s[0],
+			-a(2, 0)*s[4] + a(2, 1)*s[2] - a(2, 3)*s[0],
+		},
+		{
+			-a(1, 0)*c[3] + a(1, 1)*c[1] - a(1, 2)*c[0],
+			a(0, 0)*c[3] - a(0, 1)*c[1] + a(0, 2)*c[0],
+			-a(3, 0)*s[3] + a(3, 1)*s[1] - a(3, 2)*s[0],
+			a(2, 0)*s[3] - a(2, 1)*s[1] + a(2, 2)*s[0],
+		},
+	}
+
+	result := Matrix4f{}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			result.M[i][j] = float32(inv[i][j] / det)
+		}
+	}
+
+	return result
+}
+
+// ToGLColumnMajor returns the elements of m column after column, the layout
+// glUniformMatrix4fv() and glLoadMatrixf() expect when they don't transpose.
+func (m Matrix4f) ToGLColumnMajor() [16]float32 {
+	return m.Transpose().ToRowMajor()
+}
+
+// ToRowMajor returns the elements of m row after row, the layout of M. Pass
+// it to glUniformMatrix4fv() with transpose set to GL_TRUE.
+func (m Matrix4f) ToRowMajor() [16]float32 {
+	elements := [16]float32{}
+	for i := 0; i < 4; i++ {
+		copy(elements[i*4:], m.M[i][:])
+	}
+
+	return elements
+}
@@ -0,0 +1,102 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+func approxMatrix4f(expMatrix, calcMatrix Matrix4f, delta float32) bool {
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			if !approxFloat(expMatrix.M[i][j], calcMatrix.M[i][j], delta) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func TestMatrix4fFromPose(t *testing.T) {
+	pose := Posef{
+		Orientation: QuatfFromAxisAngle(Vector3f{1, -2, 0.5}, 1.1),
+		Position:    Vector3f{0.1, 1.6, -0.4},
+	}
+
+	m := Matrix4f_FromPose(pose)
+	if exp := Matrix4f_Translation(pose.Position).Mul(Matrix4f_Rotation(pose.Orientation)); !approxMatrix4f(exp, m, 1e-6) {
+		t.Errorf("Expected the translation times the rotation %v instead of %v", exp, m)
+	}
+
+	v := Vector3f{-0.3, 0.2, 1.5}
+	if exp, result := vectorAdd(pose.Orientation.Rotate(v), pose.Position), m.Transform(v); !approxVector3f(exp, result, 1e-5) {
+		t.Errorf("Expected %v instead of %v", exp, result)
+	}
+
+	if det := m.Determinant(); !approxFloat(1, det, 1e-5) {
+		t.Errorf("Expected a determinant of 1 instead of %f", det)
+	}
+
+	if result := m.Mul(m.Inverse()); !approxMatrix4f(Matrix4f_Identity(), result, 1e-5) {
+		t.Errorf("Expected the identity instead of %v", result)
+	}
+}
+
+func TestMatrix4fInverse(t *testing.T) {
+	fov := FovPort{UpTan: 1.3292863, DownTan: 1.3292863, LeftTan: 1.0586576, RightTan: 1.092368}
+
+	for _, m := range []Matrix4f{
+		projection(fov, 0.1, 1000, true),
+		{M: [4][4]float32{{2, 0, 1, 3}, {1, 1, 0, -1}, {0, 3, 1, 2}, {1, 0, 0, 1}}},
+	} {
+		if result := m.Inverse().Mul(m); !approxMatrix4f(Matrix4f_Identity(), result, 1e-4) {
+			t.Errorf("Expected the inverse of %v to undo it instead of %v", m, result)
+		}
+	}
+
+	singular := Matrix4f{M: [4][4]float32{{1, 2, 3, 4}, {2, 4, 6, 8}, {0, 1, 0, 1}, {1, 0, 0, 1}}}
+	if det := singular.Determinant(); det != 0 {
+		t.Errorf("Expected a determinant of 0 instead of %f", det)
+	}
+
+	if result := singular.Inverse(); result != singular {
+		t.Errorf("Expected the inverse of a singular matrix to be the matrix itself instead of %v", result)
+	}
+}
+
+func TestMatrix4fLookAt(t *testing.T) {
+	eye, target, up := Vector3f{1, 2, 3}, Vector3f{1, 2, -7}, Vector3f{0, 1, 0}
+
+	// Looking down -Z from eye is the same as standing at eye.
+	if exp, result := Matrix4f_Translation(Vector3f{-1, -2, -3}), Matrix4f_LookAtRH(eye, target, up); !approxMatrix4f(exp, result, 1e-6) {
+		t.Errorf("Expected %v instead of %v", exp, result)
+	}
+
+	// Looking to the left is the inverse of the pose turned to the left.
+	pose := Posef{Orientation: QuatfFromAxisAngle(Vector3f{Y: 1}, math.Pi/2), Position: eye}
+	if exp, result := Matrix4f_FromPose(pose).Inverse(), Matrix4f_LookAtRH(eye, Vector3f{-4, 2, 3}, up); !approxMatrix4f(exp, result, 1e-6) {
+		t.Errorf("Expected %v instead of %v", exp, result)
+	}
+
+	if result := Matrix4f_LookAtLH(eye, target, up).Transform(target); !approxVector3f(Vector3f{Z: 10}, result, 1e-5) {
+		t.Errorf("Expected the target in front of a left-handed camera instead of at %v", result)
+	}
+}
+
+func TestMatrix4fLayouts(t *testing.T) {
+	m := Matrix4f_Translation(Vector3f{7, 8, 9})
+
+	expGL := [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 7, 8, 9, 1}
+	if result := m.ToGLColumnMajor(); result != expGL {
+		t.Errorf("Expected the translation in elements 12 to 14 %v instead of %v", expGL, result)
+	}
+
+	expRows := [16]float32{1, 0, 0, 7, 0, 1, 0, 8, 0, 0, 1, 9, 0, 0, 0, 1}
+	if result := m.ToRowMajor(); result != expRows {
+		t.Errorf("Expected %v instead of %v", expRows, result)
+	}
+
+	if result := m.Transpose().Transpose(); result != m {
+		t.Errorf("Expected %v instead of %v", m, result)
+	}
+}
@@ -0,0 +1,588 @@
+package ovr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// ****************************************************************************
+// **************************** [ Motion sources ] ****************************
+// ****************************************************************************
+
+// A motionSample holds the head angles (yaw, pitch, roll) in radians and the
+// head position in meters at some point in time, together with their first
+// and second derivatives.
+type motionSample struct {
+	angles     [3]float64
+	dAngles    [3]float64
+	ddAngles   [3]float64
+	position   [3]float64
+	dPosition  [3]float64
+	ddPosition [3]float64
+}
+
+func (sample *motionSample) add(other motionSample) {
+	for i := 0; i < 3; i++ {
+		sample.angles[i] += other.angles[i]
+		sample.dAngles[i] += other.dAngles[i]
+		sample.ddAngles[i] += other.ddAngles[i]
+		sample.position[i] += other.position[i]
+		sample.dPosition[i] += other.dPosition[i]
+		sample.ddPosition[i] += other.ddPosition[i]
+	}
+}
+
+// A Motion is a head movement that can be part of a MotionScript. All motions
+// start out at the origin, looking straight ahead.
+type Motion interface {
+	sample(t float64) motionSample
+}
+
+// Implemented by the motions that repeat or move on after some time. It
+// returns the name and the value of that time, which has to be positive.
+type timedMotion interface {
+	timing() (string, float64)
+}
+
+// Returns A·sin(2πt/period) and its first and second derivatives.
+func sine(amplitude, period, t float64) (float64, float64, float64) {
+	w := 2 * math.Pi / period
+	sin, cos := math.Sincos(w * t)
+
+	return amplitude * sin, amplitude * w * cos, -amplitude * w * w * sin
+}
+
+// A YawSweep turns the head left and right, Amplitude radians to each side.
+type YawSweep struct {
+	Amplitude float64 `json:"amplitude"`
+	Period    float64 `json:"period"`
+}
+
+func (motion YawSweep) timing() (string, float64) {
+	return "period", motion.Period
+}
+
+func (motion YawSweep) sample(t float64) motionSample {
+	sample := motionSample{}
+	sample.angles[0], sample.dAngles[0], sample.ddAngles[0] = sine(motion.Amplitude, motion.Period, t)
+
+	return sample
+}
+
+// A Nod tilts the head up and down, Amplitude radians in each direction.
+type Nod struct {
+	Amplitude float64 `json:"amplitude"`
+	Period    float64 `json:"period"`
+}
+
+func (motion Nod) timing() (string, float64) {
+	return "period", motion.Period
+}
+
+func (motion Nod) sample(t float64) motionSample {
+	sample := motionSample{}
+	sample.angles[1], sample.dAngles[1], sample.ddAngles[1] = sine(motion.Amplitude, motion.Period, t)
+
+	return sample
+}
+
+// A FigureEight moves the head along a figure-eight in front of the tracking
+// camera, Width meters wide and Height meters high.
+type FigureEight struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Period float64 `json:"period"`
+}
+
+func (motion FigureEight) timing() (string, float64) {
+	return "period", motion.Period
+}
+
+func (motion FigureEight) sample(t float64) motionSample {
+	sample := motionSample{}
+	sample.position[0], sample.dPosition[0], sample.ddPosition[0] = sine(motion.Width/2, motion.Period, t)
+	sample.position[1], sample.dPosition[1], sample.ddPosition[1] = sine(motion.Height/2, motion.Period/2, t)
+
+	return sample
+}
+
+// A RandomWalk wanders around at random, but always the same way for the same
+// Seed. A new target is picked every Interval seconds, no further than Angles
+// radians (yaw, pitch, roll) and Position meters from the origin. The path is
+// a cubic B-spline through the targets, so the accelerations are continuous.
+type RandomWalk struct {
+	Seed     uint64   `json:"seed"`
+	Interval float64  `json:"interval"`
+	Angles   Vector3f `json:"angles"`
+	Position Vector3f `json:"position"`
+}
+
+func (motion RandomWalk) timing() (string, float64) {
+	return "interval", motion.Interval
+}
+
+// A splitmix64 hash, so every control point of the walk can be computed on
+// its own, without having to replay the ones before it.
+func (motion RandomWalk) random(channel int, index int64) float64 {
+	z := motion.Seed + uint64(channel)*0x9E3779B97F4A7C15 + uint64(index)*0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	z ^= z >> 31
+
+	return float64(z>>11)/float64(1<<53)*2 - 1
+}
+
+func (motion RandomWalk) channel(channel int, scale float64, t float64) (float64, float64, float64) {
+	if scale == 0 || motion.Interval <= 0 {
+		return 0, 0, 0
+	}
+
+	u := t / motion.Interval
+	k := int64(math.Floor(u))
+	u -= float64(k)
+
+	// The walk starts at rest at the origin, so the first control points are
+	// zero.
+	p := [4]float64{}
+	for i := range p {
+		if index := k + int64(i) - 1; index > 1 {
+			p[i] = scale * motion.random(channel, index)
+		}
+	}
+
+	u2, u3 := u*u, u*u*u
+	value := ((1-u)*(1-u)*(1-u)*p[0] + (3*u3-6*u2+4)*p[1] + (-3*u3+3*u2+3*u+1)*p[2] + u3*p[3]) / 6
+	d := ((-3*(1-u)*(1-u))*p[0] + (9*u2-12*u)*p[1] + (-9*u2+6*u+3)*p[2] + 3*u2*p[3]) / 6
+	dd := (6*(1-u)*p[0] + (18*u-12)*p[1] + (-18*u+6)*p[2] + 6*u*p[3]) / 6
+
+	return value, d / motion.Interval, dd / (motion.Interval * motion.Interval)
+}
+
+func (motion RandomWalk) sample(t float64) motionSample {
+	sample := motionSample{}
+	angles := [3]float32{motion.Angles.X, motion.Angles.Y, motion.Angles.Z}
+	position := [3]float32{motion.Position.X, motion.Position.Y, motion.Position.Z}
+
+	for i := 0; i < 3; i++ {
+		sample.angles[i], sample.dAngles[i], sample.ddAngles[i] = motion.channel(i, float64(angles[i]), t)
+		sample.position[i], sample.dPosition[i], sample.ddPosition[i] = motion.channel(i+3, float64(position[i]), t)
+	}
+
+	return sample
+}
+
+// ****************************************************************************
+// **************************** [ Motion scripts ] ****************************
+// ****************************************************************************
+
+// A MotionSegment plays a Motion for Duration seconds, starting Start seconds
+// into the script. A Duration of zero plays the motion until the end of the
+// script. Segments that overlap are added together. Motions are not faded in
+// or out, so a segment should end where its motion is back at the origin,
+// like after a whole number of periods, or the head will jump.
+type MotionSegment struct {
+	Start    float64
+	Duration float64
+	Motion   Motion
+}
+
+// A TrackingLoss clears the Lost status bits from the tracking state between
+// Start and End. While Status_PositionTracked is lost, the position is held
+// at where it was when tracking was lost.
+type TrackingLoss struct {
+	Start float64
+	End   float64
+	Lost  uint
+}
+
+// A MotionScript describes the head motion of a simulated device. Scripts can
+// be stored as JSON, see LoadMotionScript().
+type MotionScript struct {
+	// The length of the script in seconds. If it is zero, the script ends
+	// when the last segment does.
+	Duration float64
+
+	// Restart the script when it ends, instead of holding the final pose.
+	Loop bool
+
+	Segments     []MotionSegment
+	TrackingLoss []TrackingLoss
+}
+
+func (script *MotionScript) length() float64 {
+	if script.Duration > 0 {
+		return script.Duration
+	}
+
+	length := 0.0
+	for _, segment := range script.Segments {
+		length = math.Max(length, segment.Start+segment.Duration)
+	}
+
+	return length
+}
+
+// Maps t to the time within the script. When the script doesn't loop, the
+// motion comes to a stop at the end.
+func (script *MotionScript) scriptTime(t float64) (float64, bool) {
+	length := script.length()
+
+	switch {
+	case t < 0:
+		return 0, false
+	case length <= 0:
+		return t, true
+	case script.Loop:
+		return math.Mod(t, length), true
+	case t >= length:
+		return length, false
+	}
+
+	return t, true
+}
+
+func (script *MotionScript) sample(t float64) motionSample {
+	t, moving := script.scriptTime(t)
+	length := script.length()
+
+	sample := motionSample{}
+	for _, segment := range script.Segments {
+		end := segment.Start + segment.Duration
+		if segment.Duration == 0 {
+			end = math.Inf(1)
+		}
+
+		if t < segment.Start || t > end || (t == end && end != length) {
+			continue
+		}
+
+		sample.add(segment.Motion.sample(t - segment.Start))
+	}
+
+	if !moving {
+		sample.dAngles, sample.ddAngles = [3]float64{}, [3]float64{}
+		sample.dPosition, sample.ddPosition = [3]float64{}, [3]float64{}
+	}
+
+	return sample
+}
+
+// Returns the status bits that are lost at time t, and since when position
+// tracking has been lost.
+func (script *MotionScript) lostStatus(t float64) (uint, float64) {
+	t, _ = script.scriptTime(t)
+
+	lost, positionLostAt := uint(0), math.Inf(1)
+	for _, loss := range script.TrackingLoss {
+		if t < loss.Start || t >= loss.End {
+			continue
+		}
+
+		lost |= loss.Lost
+		if loss.Lost&Status_PositionTracked != 0 {
+			positionLostAt = math.Min(positionLostAt, loss.Start)
+		}
+	}
+
+	return lost, positionLostAt
+}
+
+// PoseState returns the head pose t seconds into the script. The orientation
+// is built from yaw, pitch and roll in that order, like libOVR does. Angular
+// velocity and acceleration are in the frame of the head, linear velocity and
+// acceleration in the frame of the tracking origin.
+func (script *MotionScript) PoseState(t float64) PoseStatef {
+	sample := script.sample(t)
+	if lost, positionLostAt := script.lostStatus(t); lost&Status_PositionTracked != 0 {
+		held := script.sample(positionLostAt)
+		sample.position = held.position
+		sample.dPosition, sample.ddPosition = [3]float64{}, [3]float64{}
+	}
+
+	return sample.poseState(t)
+}
+
+func (sample motionSample) poseState(t float64) PoseStatef {
+	yaw, pitch, roll := sample.angles[0], sample.angles[1], sample.angles[2]
+	dy, dp, dr := sample.dAngles[0], sample.dAngles[1], sample.dAngles[2]
+	ddy, ddp, ddr := sample.ddAngles[0], sample.ddAngles[1], sample.ddAngles[2]
+
+	sy, cy := math.Sincos(yaw / 2)
+	sp, cp := math.Sincos(pitch / 2)
+	sr, cr := math.Sincos(roll / 2)
+
+	// q = qYaw * qPitch * qRoll
+	orientation := Quatf{
+		X: float32(cy*sp*cr + sy*cp*sr),
+		Y: float32(sy*cp*cr - cy*sp*sr),
+		Z: float32(cy*cp*sr - sy*sp*cr),
+		W: float32(cy*cp*cr + sy*sp*sr),
+	}
+
+	// The angular velocity in the frame of the head is Rz'·Rx'·(dy·Y) +
+	// Rz'·(dp·X) + dr·Z, and the acceleration is its derivative.
+	sinP, cosP := math.Sincos(pitch)
+	sinR, cosR := math.Sincos(roll)
+
+	angularVelocity := Vector3f{
+		X: float32(dp*cosR + dy*cosP*sinR),
+		Y: float32(-dp*sinR + dy*cosP*cosR),
+		Z: float32(dr - dy*sinP),
+	}
+
+	angularAcceleration := Vector3f{
+		X: float32(ddp*cosR - dp*dr*sinR + ddy*cosP*sinR - dy*dp*sinP*sinR + dy*dr*cosP*cosR),
+		Y: float32(-ddp*sinR - dp*dr*cosR + ddy*cosP*cosR - dy*dp*sinP*cosR - dy*dr*cosP*sinR),
+		Z: float32(ddr - ddy*sinP - dy*dp*cosP),
+	}
+
+	return PoseStatef{
+		ThePose: Posef{
+			Orientation: orientation,
+			Position:    Vector3f{float32(sample.position[0]), float32(sample.position[1]), float32(sample.position[2])},
+		},
+		AngularVelocity:     angularVelocity,
+		LinearVelocity:      Vector3f{float32(sample.dPosition[0]), float32(sample.dPosition[1]), float32(sample.dPosition[2])},
+		AngularAcceleration: angularAcceleration,
+		LinearAcceleration:  Vector3f{float32(sample.ddPosition[0]), float32(sample.ddPosition[1]), float32(sample.ddPosition[2])},
+		TimeInSeconds:       t,
+	}
+}
+
+// ****************************************************************************
+// ************************* [ Motion script files ] **************************
+// ****************************************************************************
+
+// The version of the motion script file format.
+const motionScriptVersion = 1
+
+// A motion script file is a JSON document like this:
+//
+//	{
+//	  "version": 1,
+//	  "duration": 20,
+//	  "loop": true,
+//	  "segments": [
+//	    {"type": "yawSweep", "start": 0, "duration": 8, "amplitude": 0.7, "period": 4},
+//	    {"type": "nod", "start": 8, "duration": 4, "amplitude": 0.3, "period": 2},
+//	    {"type": "figureEight", "width": 0.3, "height": 0.1, "period": 5},
+//	    {"type": "randomWalk", "start": 12, "duration": 8, "seed": 42, "interval": 0.5,
+//	     "angles": {"X": 0.5, "Y": 0.2, "Z": 0.05}, "position": {"X": 0.1, "Y": 0.05, "Z": 0.1}}
+//	  ],
+//	  "trackingLoss": [
+//	    {"start": 5, "end": 6.5, "lost": ["PositionTracked", "CameraPoseTracked"]}
+//	  ]
+//	}
+//
+// Angles are in radians, distances in meters and times in seconds.
+type motionScriptJSON struct {
+	Version      int                `json:"version"`
+	Duration     float64            `json:"duration,omitempty"`
+	Loop         bool               `json:"loop,omitempty"`
+	Segments     []json.RawMessage  `json:"segments"`
+	TrackingLoss []trackingLossJSON `json:"trackingLoss,omitempty"`
+}
+
+type motionSegmentJSON struct {
+	Type     string  `json:"type"`
+	Start    float64 `json:"start,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+type trackingLossJSON struct {
+	Start float64  `json:"start"`
+	End   float64  `json:"end"`
+	Lost  []string `json:"lost"`
+}
+
+var motionTypes = map[string]func() Motion{
+	"yawSweep":    func() Motion { return &YawSweep{} },
+	"nod":         func() Motion { return &Nod{} },
+	"figureEight": func() Motion { return &FigureEight{} },
+	"randomWalk":  func() Motion { return &RandomWalk{} },
+}
+
+var statusNames = map[string]uint{
+	"OrientationTracked": Status_OrientationTracked,
+	"PositionTracked":    Status_PositionTracked,
+	"CameraPoseTracked":  Status_CameraPoseTracked,
+	"PositionConnected":  Status_PositionConnected,
+	"HmdConnected":       Status_HmdConnected,
+}
+
+func motionTypeName(motion Motion) (string, error) {
+	switch motion.(type) {
+	case YawSweep, *YawSweep:
+		return "yawSweep", nil
+	case Nod, *Nod:
+		return "nod", nil
+	case FigureEight, *FigureEight:
+		return "figureEight", nil
+	case RandomWalk, *RandomWalk:
+		return "randomWalk", nil
+	}
+
+	return "", fmt.Errorf("unsupported motion type %T", motion)
+}
+
+func (script MotionScript) MarshalJSON() ([]byte, error) {
+	doc := motionScriptJSON{
+		Version:  motionScriptVersion,
+		Duration: script.Duration,
+		Loop:     script.Loop,
+		Segments: []json.RawMessage{},
+	}
+
+	for _, segment := range script.Segments {
+		typeName, err := motionTypeName(segment.Motion)
+		if err != nil {
+			return nil, err
+		}
+
+		// Merge the segment timing into the parameters of the motion.
+		fields := map[string]interface{}{}
+		params, err := json.Marshal(segment.Motion)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(params, &fields); err != nil {
+			return nil, err
+		}
+
+		fields["type"] = typeName
+		if segment.Start != 0 {
+			fields["start"] = segment.Start
+		}
+		if segment.Duration != 0 {
+			fields["duration"] = segment.Duration
+		}
+
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Segments = append(doc.Segments, raw)
+	}
+
+	for _, loss := range script.TrackingLoss {
+		lossJSON := trackingLossJSON{Start: loss.Start, End: loss.End, Lost: []string{}}
+		for _, name := range []string{"OrientationTracked", "PositionTracked", "CameraPoseTracked", "PositionConnected", "HmdConnected"} {
+			if loss.Lost&statusNames[name] != 0 {
+				lossJSON.Lost = append(lossJSON.Lost, name)
+			}
+		}
+
+		doc.TrackingLoss = append(doc.TrackingLoss, lossJSON)
+	}
+
+	return json.Marshal(doc)
+}
+
+func (script *MotionScript) UnmarshalJSON(data []byte) error {
+	doc := motionScriptJSON{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Version != motionScriptVersion {
+		return fmt.Errorf("unsupported motion script version %d", doc.Version)
+	}
+
+	*script = MotionScript{Duration: doc.Duration, Loop: doc.Loop}
+
+	for i, raw := range doc.Segments {
+		header := motionSegmentJSON{}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return err
+		}
+
+		newMotion, ok := motionTypes[header.Type]
+		if !ok {
+			return fmt.Errorf("segment %d: unknown motion type '%s'", i, header.Type)
+		}
+
+		motion := newMotion()
+		if err := json.Unmarshal(raw, motion); err != nil {
+			return fmt.Errorf("segment %d: %s", i, err)
+		}
+
+		script.Segments = append(script.Segments, MotionSegment{
+			Start:    header.Start,
+			Duration: header.Duration,
+			Motion:   motion,
+		})
+	}
+
+	for i, lossJSON := range doc.TrackingLoss {
+		loss := TrackingLoss{Start: lossJSON.Start, End: lossJSON.End}
+		for _, name := range lossJSON.Lost {
+			bit, ok := statusNames[name]
+			if !ok {
+				return fmt.Errorf("tracking loss %d: unknown status '%s'", i, name)
+			}
+
+			loss.Lost |= bit
+		}
+
+		script.TrackingLoss = append(script.TrackingLoss, loss)
+	}
+
+	return script.validate()
+}
+
+func (script *MotionScript) validate() error {
+	for i, segment := range script.Segments {
+		if segment.Motion == nil {
+			return fmt.Errorf("segment %d: no motion", i)
+		}
+		if segment.Start < 0 || segment.Duration < 0 {
+			return fmt.Errorf("segment %d: negative start or duration", i)
+		}
+		if timed, ok := segment.Motion.(timedMotion); ok {
+			if name, value := timed.timing(); !(value > 0) {
+				return fmt.Errorf("segment %d: the %s must be positive", i, name)
+			}
+		}
+	}
+
+	for i, loss := range script.TrackingLoss {
+		if loss.End < loss.Start {
+			return fmt.Errorf("tracking loss %d: ends before it starts", i)
+		}
+	}
+
+	if script.Loop && script.length() <= 0 {
+		return errors.New("a looping script needs a duration")
+	}
+
+	return nil
+}
+
+// LoadMotionScript reads a motion script from a JSON file.
+func LoadMotionScript(filename string) (*MotionScript, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	script := &MotionScript{}
+	if err := json.Unmarshal(data, script); err != nil {
+		return nil, err
+	}
+
+	return script, nil
+}
+
+// Save writes the motion script to a JSON file.
+func (script *MotionScript) Save(filename string) error {
+	data, err := json.MarshalIndent(script, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
@@ -0,0 +1,149 @@
+package ovr
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func testMotionScript() *MotionScript {
+	return &MotionScript{
+		Duration: 10,
+		Loop:     true,
+		Segments: []MotionSegment{
+			{Motion: YawSweep{Amplitude: 0.7, Period: 4}},
+			{Motion: Nod{Amplitude: 0.3, Period: 2.5}},
+			{Motion: FigureEight{Width: 0.3, Height: 0.1, Period: 5}},
+			{Start: 2, Duration: 6, Motion: RandomWalk{Seed: 42, Interval: 0.5, Angles: Vector3f{0.2, 0.1, 0.05}, Position: Vector3f{0.05, 0.05, 0.05}}},
+		},
+		TrackingLoss: []TrackingLoss{
+			{Start: 3, End: 4, Lost: Status_PositionTracked | Status_CameraPoseTracked},
+		},
+	}
+}
+
+func TestMotionScriptDerivatives(t *testing.T) {
+	script := testMotionScript()
+	const h = 0.0005
+
+	for _, tm := range []float64{0.3, 1.7, 2.9, 5.25, 7.8} {
+		poseState := script.PoseState(tm)
+		before, after := script.PoseState(tm-h), script.PoseState(tm+h)
+
+		// The change in orientation, in the frame of the head.
+		delta := before.ThePose.Orientation.Conjugate().Mul(after.ThePose.Orientation)
+		angularVelocity := Vector3f{delta.X / h, delta.Y / h, delta.Z / h}
+
+		linearVelocity := Vector3f{
+			(after.ThePose.Position.X - before.ThePose.Position.X) / (2 * h),
+			(after.ThePose.Position.Y - before.ThePose.Position.Y) / (2 * h),
+			(after.ThePose.Position.Z - before.ThePose.Position.Z) / (2 * h),
+		}
+
+		angularAcceleration := Vector3f{
+			(after.AngularVelocity.X - before.AngularVelocity.X) / (2 * h),
+			(after.AngularVelocity.Y - before.AngularVelocity.Y) / (2 * h),
+			(after.AngularVelocity.Z - before.AngularVelocity.Z) / (2 * h),
+		}
+
+		checkVec := func(name string, expVec, calcVec Vector3f, delta float32) {
+			if !approxFloat(expVec.X, calcVec.X, delta) || !approxFloat(expVec.Y, calcVec.Y, delta) || !approxFloat(expVec.Z, calcVec.Z, delta) {
+				t.Errorf("At %.2fs, expected %s %v to be within range of %v", tm, name, calcVec, expVec)
+			}
+		}
+
+		checkVec("AngularVelocity", angularVelocity, poseState.AngularVelocity, 0.01)
+		checkVec("AngularAcceleration", angularAcceleration, poseState.AngularAcceleration, 0.05)
+
+		// Position is held while position tracking is lost.
+		if tm < 3 || tm >= 4 {
+			checkVec("LinearVelocity", linearVelocity, poseState.LinearVelocity, 0.01)
+		}
+	}
+}
+
+func TestMotionScriptStartsAtRest(t *testing.T) {
+	poseState := testMotionScript().PoseState(0)
+
+	if poseState.ThePose.Orientation != (Quatf{W: 1}) || poseState.ThePose.Position != (Vector3f{}) {
+		t.Errorf("Expected the script to start at the origin, instead of %v", poseState.ThePose)
+	}
+}
+
+func TestMotionScriptJSON(t *testing.T) {
+	script := testMotionScript()
+	filename := filepath.Join(t.TempDir(), "script.json")
+
+	if err := script.Save(filename); err != nil {
+		t.Fatalf("Expected Save() to succeed, instead of '%s'", err)
+	}
+
+	loaded, err := LoadMotionScript(filename)
+	if err != nil {
+		t.Fatalf("Expected LoadMotionScript() to succeed, instead of '%s'", err)
+	}
+
+	for _, tm := range []float64{0.5, 2.5, 3.5, 9.9, 12.5} {
+		if expPose, calcPose := script.PoseState(tm), loaded.PoseState(tm); expPose != calcPose {
+			t.Errorf("At %.2fs, expected the loaded script to return %v, instead of %v", tm, expPose, calcPose)
+		}
+	}
+
+	badScripts := []string{
+		`{"version": 2, "segments": []}`,
+		`{"version": 1, "segments": [{"type": "cartwheel"}]}`,
+		`{"version": 1, "segments": [], "trackingLoss": [{"start": 1, "end": 2, "lost": ["Everything"]}]}`,
+		`{"version": 1, "segments": [{"type": "yawSweep", "amplitude": 0.5}]}`,
+		`{"version": 1, "segments": [{"type": "nod", "amplitude": 0.3, "period": -2}]}`,
+		`{"version": 1, "segments": [{"type": "figureEight", "width": 0.3, "height": 0.1, "period": 0}]}`,
+		`{"version": 1, "segments": [{"type": "randomWalk", "seed": 42, "angles": {"X": 0.5}}]}`,
+	}
+
+	for _, badScript := range badScripts {
+		if err := json.Unmarshal([]byte(badScript), &MotionScript{}); err == nil {
+			t.Errorf("Expected '%s' to be rejected", badScript)
+		}
+	}
+}
+
+func TestSimulatorMotionScript(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+	if err := hmd.Simulator().SetMotionScript(testMotionScript(), 100); err != nil {
+		t.Fatalf("Expected SetMotionScript() to succeed, instead of '%s'", err)
+	}
+
+	if flags := hmd.GetTrackingState(103.5).StatusFlags; flags&(Status_PositionTracked|Status_CameraPoseTracked) != 0 {
+		t.Errorf("Expected position tracking to be lost 3.5s into the script, instead of status 0x%x", flags)
+	}
+
+	if flags := hmd.GetTrackingState(104.5).StatusFlags; flags&Status_PositionTracked == 0 {
+		t.Errorf("Expected position tracking to be back 4.5s into the script, instead of status 0x%x", flags)
+	}
+
+	if expPose, calcPose := testMotionScript().PoseState(1.5).ThePose, hmd.GetTrackingState(101.5).HeadPose.ThePose; expPose != calcPose {
+		t.Errorf("Expected the device to follow the script, instead of %v", calcPose)
+	}
+}
+
+func TestSimulatorRejectsInvalidMotionScript(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	for _, motion := range []Motion{YawSweep{Amplitude: 1}, &Nod{Amplitude: 1}, RandomWalk{Angles: Vector3f{1, 1, 1}}} {
+		script := &MotionScript{Segments: []MotionSegment{{Motion: motion}}}
+		if err := hmd.Simulator().SetMotionScript(script, 0); err == nil {
+			t.Errorf("Expected a script with %#v to be rejected", motion)
+		}
+	}
+
+	if pose := hmd.GetTrackingState(1).HeadPose.ThePose; pose.Orientation != (Quatf{W: 1}) {
+		t.Errorf("Expected the device to hold still after a rejected script, instead of %v", pose)
+	}
+
+	if err := hmd.Simulator().SetMotionScript(nil, 0); err != nil {
+		t.Errorf("Expected a nil script to be accepted, instead of '%s'", err)
+	}
+}
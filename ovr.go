@@ -1,35 +1,6 @@
 package ovr
 
-/*
-#cgo darwin CFLAGS: -DOVR_OS_MAC
-#cgo darwin LDFLAGS: -framework CoreFoundation -framework IOKit -framework CoreGraphics -framework OpenGL -lc++ -lovr
-#cgo windows CFLAGS: -DOVR_OS_WIN32 -I C:/libovr_0.4/dynamic
-#cgo windows,386 LDFLAGS: -L C:/libovr_0.4/x86 -lovr
-#cgo windows,amd64 LDFLAGS: -L C:/libovr_0.4/x64 -lovr
-#include <stdlib.h>
-#include <string.h>
-#include <OVR_CAPI_GL.h>
-*/
-import "C"
-
-import (
-	"errors"
-	"unsafe"
-)
-
-func ovrBool(b bool) C.ovrBool {
-	if b {
-		return 1
-	}
-	return 0
-}
-
-func newBool(b C.ovrBool) bool {
-	if b == 1 {
-		return true
-	}
-	return false
-}
+import "errors"
 
 // ****************************************************************************
 // ************************ [ Simple Math Structures ] ************************
@@ -41,42 +12,18 @@ type Vector2i struct {
 	Y int
 }
 
-func (vector Vector2i) toC() C.ovrVector2i {
-	return C.ovrVector2i{x: C.int(vector.X), y: C.int(vector.Y)}
-}
-
-func newVector2i(vector C.ovrVector2i) Vector2i {
-	return Vector2i{X: int(vector.x), Y: int(vector.y)}
-}
-
 // A 2D size with integer components.
 type Sizei struct {
 	W int
 	H int
 }
 
-func (size Sizei) toC() C.ovrSizei {
-	return C.ovrSizei{w: C.int(size.W), h: C.int(size.H)}
-}
-
-func newSizei(size C.ovrSizei) Sizei {
-	return Sizei{W: int(size.w), H: int(size.h)}
-}
-
 // A 2D rectangle with a position and size.
 type Recti struct {
 	Pos  Vector2i
 	Size Sizei
 }
 
-func (rect Recti) toC() C.ovrRecti {
-	return C.ovrRecti{Pos: rect.Pos.toC(), Size: rect.Size.toC()}
-}
-
-func newRecti(rect C.ovrRecti) Recti {
-	return Recti{Pos: newVector2i(rect.Pos), Size: newSizei(rect.Size)}
-}
-
 // A quaternion rotation.
 type Quatf struct {
 	X float32
@@ -85,28 +32,12 @@ type Quatf struct {
 	W float32
 }
 
-func (quat Quatf) toC() C.ovrQuatf {
-	return C.ovrQuatf{x: C.float(quat.X), y: C.float(quat.Y), z: C.float(quat.Z), w: C.float(quat.W)}
-}
-
-func newQuatf(quat C.ovrQuatf) Quatf {
-	return Quatf{X: float32(quat.x), Y: float32(quat.y), Z: float32(quat.z), W: float32(quat.w)}
-}
-
 // A 2D vector with float components.
 type Vector2f struct {
 	X float32
 	Y float32
 }
 
-func (vector Vector2f) toC() C.ovrVector2f {
-	return C.ovrVector2f{x: C.float(vector.X), y: C.float(vector.Y)}
-}
-
-func newVector2f(vector C.ovrVector2f) Vector2f {
-	return Vector2f{X: float32(vector.x), Y: float32(vector.y)}
-}
-
 // A 3D vector with float components.
 type Vector3f struct {
 	X float32
@@ -114,63 +45,20 @@ type Vector3f struct {
 	Z float32
 }
 
-func (vector Vector3f) toC() C.ovrVector3f {
-	return C.ovrVector3f{x: C.float(vector.X), y: C.float(vector.Y), z: C.float(vector.Z)}
-}
-
-func newVector3f(vector C.ovrVector3f) Vector3f {
-	return Vector3f{X: float32(vector.x), Y: float32(vector.y), Z: float32(vector.z)}
-}
-
-// A 4x4 matrix with float elements.
+// A 4x4 matrix with float elements. Like in libOVR, M[row][col] is row-major,
+// matrices transform column vectors (v' = M·v) and the translation is in the
+// last column. OpenGL expects the transpose of this layout, see
+// ToGLColumnMajor().
 type Matrix4f struct {
 	M [4][4]float32
 }
 
-func (matrix Matrix4f) toC() C.ovrMatrix4f {
-	M := [4][4]C.float{}
-
-	for x := 0; x < 4; x++ {
-		for y := 0; y < 4; y++ {
-			M[x][y] = C.float(matrix.M[x][y])
-		}
-	}
-
-	return C.ovrMatrix4f{M: M}
-}
-
-func newMatrix4f(matrix C.ovrMatrix4f) Matrix4f {
-	m := Matrix4f{}
-
-	for x := 0; x < 4; x++ {
-		for y := 0; y < 4; y++ {
-			m.M[x][y] = float32(matrix.M[x][y])
-		}
-	}
-
-	return m
-}
-
 // Position and orientation together.
 type Posef struct {
 	Orientation Quatf
 	Position    Vector3f
 }
 
-func (posef Posef) toC() C.ovrPosef {
-	return C.ovrPosef{
-		Orientation: posef.Orientation.toC(),
-		Position:    posef.Position.toC(),
-	}
-}
-
-func newPosef(posef C.ovrPosef) Posef {
-	return Posef{
-		Orientation: newQuatf(posef.Orientation),
-		Position:    newVector3f(posef.Position),
-	}
-}
-
 // A full pose (rigid body) configuration with first and second derivatives.
 type PoseStatef struct {
 	ThePose             Posef
@@ -181,28 +69,6 @@ type PoseStatef struct {
 	TimeInSeconds       float64
 }
 
-func (poseState PoseStatef) toC() C.ovrPoseStatef {
-	return C.ovrPoseStatef{
-		ThePose:             poseState.ThePose.toC(),
-		AngularVelocity:     poseState.AngularVelocity.toC(),
-		LinearVelocity:      poseState.LinearVelocity.toC(),
-		AngularAcceleration: poseState.AngularAcceleration.toC(),
-		LinearAcceleration:  poseState.LinearAcceleration.toC(),
-		TimeInSeconds:       C.double(poseState.TimeInSeconds),
-	}
-}
-
-func newPoseStatef(poseStatef C.ovrPoseStatef) PoseStatef {
-	return PoseStatef{
-		ThePose:             newPosef(poseStatef.ThePose),
-		AngularVelocity:     newVector3f(poseStatef.AngularVelocity),
-		LinearVelocity:      newVector3f(poseStatef.LinearVelocity),
-		AngularAcceleration: newVector3f(poseStatef.AngularAcceleration),
-		LinearAcceleration:  newVector3f(poseStatef.LinearAcceleration),
-		TimeInSeconds:       float64(poseStatef.TimeInSeconds),
-	}
-}
-
 // Field Of View (FOV) in tangent of the angle units.
 type FovPort struct {
 	UpTan    float32
@@ -211,83 +77,65 @@ type FovPort struct {
 	RightTan float32
 }
 
-func (fov FovPort) toC() C.ovrFovPort {
-	return C.ovrFovPort{
-		UpTan:    C.float(fov.UpTan),
-		DownTan:  C.float(fov.DownTan),
-		LeftTan:  C.float(fov.LeftTan),
-		RightTan: C.float(fov.RightTan),
-	}
-}
-
-func newFovPort(fov C.ovrFovPort) FovPort {
-	return FovPort{
-		UpTan:    float32(fov.UpTan),
-		DownTan:  float32(fov.DownTan),
-		LeftTan:  float32(fov.LeftTan),
-		RightTan: float32(fov.RightTan),
-	}
-}
-
 // ****************************************************************************
 // ******************************* [ HMD types ] ******************************
 // ****************************************************************************
 
 // Enumerates all HMD types that we support.
 const (
-	Hmd_None  = C.ovrHmd_None
-	Hmd_DKHD  = C.ovrHmd_DKHD
-	Hmd_DK1   = C.ovrHmd_DK1
-	Hmd_DK2   = C.ovrHmd_DK2
-	Hmd_Other = C.ovrHmd_Other
+	Hmd_None  = 0
+	Hmd_DK1   = 3
+	Hmd_DKHD  = 4
+	Hmd_DK2   = 6
+	Hmd_Other = 7
 )
 
-type HmdType C.ovrHmdType
+type HmdType int
 
 // HMD capability bits reported by device.
 const (
 	// Read-only flags.
-	HmdCap_Present       = C.ovrHmdCap_Present
-	HmdCap_Available     = C.ovrHmdCap_Available
-	HmdCap_Captured      = C.ovrHmdCap_Captured
-	HmdCap_ExtendDesktop = C.ovrHmdCap_ExtendDesktop
+	HmdCap_Present       = 0x0001
+	HmdCap_Available     = 0x0002
+	HmdCap_Captured      = 0x0004
+	HmdCap_ExtendDesktop = 0x0008
 
 	// Modifiable flags.
-	HmdCap_NoMirrorToWindow  = C.ovrHmdCap_NoMirrorToWindow
-	HmdCap_DisplayOff        = C.ovrHmdCap_DisplayOff
-	HmdCap_LowPersistence    = C.ovrHmdCap_LowPersistence
-	HmdCap_DynamicPrediction = C.ovrHmdCap_DynamicPrediction
-	HmdCap_NoVSync           = C.ovrHmdCap_NoVSync
-
-	HmdCap_Writable_Mask = C.ovrHmdCap_Writable_Mask
-	HmdCap_Service_Mask  = C.ovrHmdCap_Service_Mask
+	HmdCap_NoMirrorToWindow  = 0x2000
+	HmdCap_DisplayOff        = 0x0040
+	HmdCap_LowPersistence    = 0x0080
+	HmdCap_DynamicPrediction = 0x0200
+	HmdCap_NoVSync           = 0x1000
+
+	HmdCap_Writable_Mask = 0x33F0
+	HmdCap_Service_Mask  = 0x23F0
 )
 
-type HmdCaps C.ovrHmdCaps
+type HmdCaps uint
 
 // Tracking capability bits reported by the device.
 const (
-	TrackingCap_Orientation      = C.ovrTrackingCap_Orientation
-	TrackingCap_MagYawCorrection = C.ovrTrackingCap_MagYawCorrection
-	TrackingCap_Position         = C.ovrTrackingCap_Position
-	TrackingCap_Idle             = C.ovrTrackingCap_Idle
+	TrackingCap_Orientation      = 0x0010
+	TrackingCap_MagYawCorrection = 0x0020
+	TrackingCap_Position         = 0x0040
+	TrackingCap_Idle             = 0x0100
 )
 
-type TrackingCaps C.ovrTrackingCaps
+type TrackingCaps uint
 
 // Distortion capability bits reported by device.
 const (
-	DistortionCap_Chromatic                  = C.ovrDistortionCap_Chromatic
-	DistortionCap_TimeWarp                   = C.ovrDistortionCap_TimeWarp
-	DistortionCap_Vignette                   = C.ovrDistortionCap_Vignette
-	DistortionCap_NoRestore                  = C.ovrDistortionCap_NoRestore
-	DistortionCap_FlipInput                  = C.ovrDistortionCap_FlipInput
-	DistortionCap_SRGB                       = C.ovrDistortionCap_SRGB
-	DistortionCap_Overdrive                  = C.ovrDistortionCap_Overdrive
-	DistortionCap_ProfileNoTimewarpSpinWaits = C.ovrDistortionCap_ProfileNoTimewarpSpinWaits
+	DistortionCap_Chromatic                  = 0x01
+	DistortionCap_TimeWarp                   = 0x02
+	DistortionCap_Vignette                   = 0x08
+	DistortionCap_NoRestore                  = 0x10
+	DistortionCap_FlipInput                  = 0x20
+	DistortionCap_SRGB                       = 0x40
+	DistortionCap_Overdrive                  = 0x80
+	DistortionCap_ProfileNoTimewarpSpinWaits = 0x10000
 )
 
-type DistortionCaps C.ovrDistortionCaps
+type DistortionCaps uint
 
 // Specifies which eye is being used for rendering.
 const (
@@ -296,11 +144,11 @@ const (
 	Eye_Count = 2
 )
 
-type EyeType C.ovrEyeType
+type EyeType int
 
 // This is a complete descriptor of the HMD.
 type Hmd struct {
-	hmdRef                     C.ovrHmd
+	dev                        device
 	Type                       HmdType
 	ProductName                string
 	Manufacturer               string
@@ -325,56 +173,15 @@ type Hmd struct {
 	DisplayId                  int
 }
 
-func newHmd(hmd C.ovrHmd) *Hmd {
-	if hmd == nil {
-		return nil
-	}
-
-	return &Hmd{
-		hmdRef:                     hmd,
-		Type:                       HmdType(hmd.Type),
-		ProductName:                C.GoString(hmd.ProductName),
-		Manufacturer:               C.GoString(hmd.Manufacturer),
-		VendorId:                   int(hmd.VendorId),
-		ProductId:                  int(hmd.ProductId),
-		SerialNumber:               C.GoString(&hmd.SerialNumber[0]),
-		FirmwareMajor:              int(hmd.FirmwareMajor),
-		FirmwareMinor:              int(hmd.FirmwareMinor),
-		CameraFrustumHFovInRadians: float32(hmd.CameraFrustumHFovInRadians),
-		CameraFrustumVFovInRadians: float32(hmd.CameraFrustumVFovInRadians),
-		CameraFrustumNearZInMeters: float32(hmd.CameraFrustumNearZInMeters),
-		CameraFrustumFarZInMeters:  float32(hmd.CameraFrustumFarZInMeters),
-		HmdCaps:                    uint(hmd.HmdCaps),
-		TrackingCaps:               uint(hmd.TrackingCaps),
-		DistortionCaps:             uint(hmd.DistortionCaps),
-		DefaultEyeFov: [Eye_Count]FovPort{
-			newFovPort(hmd.DefaultEyeFov[0]),
-			newFovPort(hmd.DefaultEyeFov[1]),
-		},
-		MaxEyeFov: [Eye_Count]FovPort{
-			newFovPort(hmd.MaxEyeFov[0]),
-			newFovPort(hmd.MaxEyeFov[1]),
-		},
-		EyeRenderOrder: [Eye_Count]EyeType{
-			EyeType(hmd.EyeRenderOrder[0]),
-			EyeType(hmd.EyeRenderOrder[1]),
-		},
-		Resolution:        newSizei(hmd.Resolution),
-		WindowsPos:        newVector2i(hmd.WindowsPos),
-		DisplayDeviceName: C.GoString(hmd.DisplayDeviceName),
-		DisplayId:         int(hmd.DisplayId),
-	}
-}
-
 const (
-	Status_OrientationTracked = C.ovrStatus_OrientationTracked
-	Status_PositionTracked    = C.ovrStatus_PositionTracked
-	Status_CameraPoseTracked  = C.ovrStatus_CameraPoseTracked
-	Status_PositionConnected  = C.ovrStatus_PositionConnected
-	Status_HmdConnected       = C.ovrStatus_HmdConnected
+	Status_OrientationTracked = 0x0001
+	Status_PositionTracked    = 0x0002
+	Status_CameraPoseTracked  = 0x0004
+	Status_PositionConnected  = 0x0020
+	Status_HmdConnected       = 0x0080
 )
 
-type StatusBits C.ovrStatusBits
+type StatusBits uint
 
 type SensorData struct {
 	Accelerometer Vector3f
@@ -384,26 +191,6 @@ type SensorData struct {
 	TimeInSeconds float32
 }
 
-func (data SensorData) toC() C.ovrSensorData {
-	return C.ovrSensorData{
-		Accelerometer: data.Accelerometer.toC(),
-		Gyro:          data.Gyro.toC(),
-		Magnetometer:  data.Magnetometer.toC(),
-		Temperature:   C.float(data.Temperature),
-		TimeInSeconds: C.float(data.TimeInSeconds),
-	}
-}
-
-func newSensorData(data C.ovrSensorData) SensorData {
-	return SensorData{
-		Accelerometer: newVector3f(data.Accelerometer),
-		Gyro:          newVector3f(data.Gyro),
-		Magnetometer:  newVector3f(data.Magnetometer),
-		Temperature:   float32(data.Temperature),
-		TimeInSeconds: float32(data.TimeInSeconds),
-	}
-}
-
 type TrackingState struct {
 	HeadPose          PoseStatef
 	CameraPose        Posef
@@ -412,28 +199,16 @@ type TrackingState struct {
 	StatusFlags       uint
 }
 
-func (state TrackingState) toC() C.ovrTrackingState {
-	return C.ovrTrackingState{
-		HeadPose:          state.HeadPose.toC(),
-		CameraPose:        state.CameraPose.toC(),
-		LeveledCameraPose: state.LeveledCameraPose.toC(),
-		RawSensorData:     state.RawSensorData.toC(),
-		StatusFlags:       C.uint(state.StatusFlags),
-	}
-}
-
-func newTrackingState(trackingState C.ovrTrackingState) TrackingState {
-	return TrackingState{
-		HeadPose:          newPoseStatef(trackingState.HeadPose),
-		CameraPose:        newPosef(trackingState.CameraPose),
-		LeveledCameraPose: newPosef(trackingState.LeveledCameraPose),
-		RawSensorData:     newSensorData(trackingState.RawSensorData),
-		StatusFlags:       uint(trackingState.StatusFlags),
-	}
+// Frame timing data reported by BeginFrameTiming() or BeginFrame().
+type FrameTiming struct {
+	DeltaSeconds           float32
+	ThisFrameSeconds       float64
+	TimewarpPointSeconds   float64
+	NextFrameSeconds       float64
+	ScanoutMidpointSeconds float64
+	EyeScanoutSeconds      [2]float64
 }
 
-type FrameTiming C.ovrFrameTiming
-
 type EyeRenderDesc struct {
 	Eye                       EyeType
 	Fov                       FovPort
@@ -442,37 +217,17 @@ type EyeRenderDesc struct {
 	ViewAdjust                Vector3f
 }
 
-func (desc EyeRenderDesc) toC() C.ovrEyeRenderDesc {
-	return C.ovrEyeRenderDesc{
-		Eye:                       C.ovrEyeType(desc.Eye),
-		Fov:                       desc.Fov.toC(),
-		DistortedViewport:         desc.DistortedViewport.toC(),
-		PixelsPerTanAngleAtCenter: desc.PixelsPerTanAngleAtCenter.toC(),
-		ViewAdjust:                desc.ViewAdjust.toC(),
-	}
-}
-
-func newEyeRenderDesc(eyeRenderDesc C.ovrEyeRenderDesc) EyeRenderDesc {
-	return EyeRenderDesc{
-		Eye:                       EyeType(eyeRenderDesc.Eye),
-		Fov:                       newFovPort(eyeRenderDesc.Fov),
-		DistortedViewport:         newRecti(eyeRenderDesc.DistortedViewport),
-		PixelsPerTanAngleAtCenter: newVector2f(eyeRenderDesc.PixelsPerTanAngleAtCenter),
-		ViewAdjust:                newVector3f(eyeRenderDesc.ViewAdjust),
-	}
-}
-
 const (
-	RenderAPI_None         = C.ovrRenderAPI_None
-	RenderAPI_OpenGL       = C.ovrRenderAPI_OpenGL
-	RenderAPI_Android_GLES = C.ovrRenderAPI_Android_GLES
-	RenderAPI_D39          = C.ovrRenderAPI_D3D9
-	RenderAPI_D310         = C.ovrRenderAPI_D3D10
-	RenderAPI_D311         = C.ovrRenderAPI_D3D11
-	RenderAPI_Count        = C.ovrRenderAPI_Count
+	RenderAPI_None         = 0
+	RenderAPI_OpenGL       = 1
+	RenderAPI_Android_GLES = 2
+	RenderAPI_D39          = 3
+	RenderAPI_D310         = 4
+	RenderAPI_D311         = 5
+	RenderAPI_Count        = 6
 )
 
-type RenderAPIType C.ovrRenderAPIType
+type RenderAPIType int
 
 type RenderAPIConfigHeader struct {
 	API         RenderAPIType
@@ -480,55 +235,23 @@ type RenderAPIConfigHeader struct {
 	Multisample int
 }
 
-func (configHeader RenderAPIConfigHeader) toC() C.ovrRenderAPIConfigHeader {
-	return C.ovrRenderAPIConfigHeader{
-		API:         C.ovrRenderAPIType(configHeader.API),
-		RTSize:      configHeader.RTSize.toC(),
-		Multisample: C.int(configHeader.Multisample),
-	}
+// Platform-independent rendering configuration. In C this is a union with the
+// API specific structures, which is why the platform data is kept as an
+// opaque array.
+type RenderAPIConfig struct {
+	Header       RenderAPIConfigHeader
+	PlatformData [8]uintptr
 }
 
-type RenderAPIConfig C.ovrRenderAPIConfig
-
 type TextureHeader struct {
 	API            RenderAPIType
 	TextureSize    Sizei
 	RenderViewport Recti
 }
 
-func (header TextureHeader) toC() C.ovrTextureHeader {
-	return C.ovrTextureHeader{
-		API:            C.ovrRenderAPIType(header.API),
-		TextureSize:    header.TextureSize.toC(),
-		RenderViewport: header.RenderViewport.toC(),
-	}
-}
-
-func newTextureHeader(header C.ovrTextureHeader) TextureHeader {
-	return TextureHeader{
-		API:            RenderAPIType(header.API),
-		TextureSize:    newSizei(header.TextureSize),
-		RenderViewport: newRecti(header.RenderViewport),
-	}
-}
-
 type Texture struct {
 	Header       TextureHeader
-	PlatformData [8]C.uintptr_t
-}
-
-func (texture Texture) toC() C.ovrTexture {
-	return C.ovrTexture{
-		Header:       texture.Header.toC(),
-		PlatformData: texture.PlatformData,
-	}
-}
-
-func newTexture(texture C.ovrTexture) Texture {
-	return Texture{
-		Header:       newTextureHeader(texture.Header),
-		PlatformData: texture.PlatformData,
-	}
+	PlatformData [8]uintptr
 }
 
 // ****************************************************************************
@@ -542,14 +265,16 @@ type GLConfig struct {
 }
 
 func (config GLConfig) Config() *RenderAPIConfig {
-	configData := config.OGL.toC()
-	return (*RenderAPIConfig)(unsafe.Pointer(&configData))
+	return &RenderAPIConfig{
+		Header:       config.OGL.Header,
+		PlatformData: config.OGL.platformData(),
+	}
 }
 
 // Used to pass GL eye texture data to ovrHmd_EndFrame.
 type GLTextureData struct {
 	Header TextureHeader
-	TexId  C.GLuint
+	TexId  uint32
 }
 
 // Contains platform-specific information about a texture.
@@ -562,55 +287,86 @@ type GLTexture struct {
 // ***************************** [ API interface ] ****************************
 // ****************************************************************************
 
-func InitializeRenderingShim() {
-	C.ovr_InitializeRenderingShim()
+// LibraryPath is the shared library libOVR is loaded from, when the package is
+// built with the libovr tag. It is loaded by Initialize(), or on first use by
+// the functions that don't need it, like GetTimeInSeconds(). LibraryPath only
+// takes effect before the library first loads: once it has, changing it makes
+// Initialize() and InitializeRenderingShim() fail until UnloadLibrary() is
+// called. An empty path looks libOVR up in the program itself, for when it is
+// linked in statically.
+var LibraryPath = defaultLibraryPath
+
+// UnloadLibrary unloads libOVR, so the next call to Initialize() loads it from
+// LibraryPath again. The SDK has to be shut down first, and every device
+// destroyed: it fails while sessions or devices opened with HmdCreate() are
+// open. Without the libovr build tag, it does nothing.
+func UnloadLibrary() error {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	if sessions.count > 0 {
+		return errors.New("libOVR can't be unloaded while sessions are open")
+	}
+
+	return currentBackend.unloadLibrary()
 }
 
-func Initialize() bool {
-	return C.ovr_Initialize() == 1
+// InitializeRenderingShim returns an error when libOVR can't be loaded, like
+// Initialize().
+func InitializeRenderingShim() error {
+	return currentBackend.initializeRenderingShim()
 }
 
-func Shutdown() {
-	C.ovr_Shutdown()
+// Initialize returns an error when libOVR can't be loaded, is of an
+// unsupported version or fails to initialize.
+func Initialize() error {
+	return currentBackend.initialize()
+}
+
+// Shutdown returns an error, and leaves the SDK initialized, while sessions are
+// open. The SDK is shut down when the last of them is closed instead.
+func Shutdown() error {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	if sessions.count > 0 {
+		return errors.New("the SDK can't be shut down while sessions are open")
+	}
+
+	currentBackend.shutdown()
+	return nil
 }
 
 func GetVersionString() string {
-	return C.GoString(C.ovr_GetVersionString())
+	return currentBackend.versionString()
 }
 
 func HmdDetect() int {
-	return int(C.ovrHmd_Detect())
+	return currentBackend.detect()
 }
 
 func HmdCreate(index int) *Hmd {
-	return newHmd(C.ovrHmd_Create(C.int(index)))
+	return currentBackend.create(index)
 }
 
 func HmdCreateDebug(hmdType HmdType) *Hmd {
-	return newHmd(C.ovrHmd_CreateDebug(C.ovrHmdType(hmdType)))
+	return currentBackend.createDebug(hmdType)
 }
 
 func (hmd *Hmd) Destroy() {
-	C.ovrHmd_Destroy(hmd.hmdRef)
+	hmd.dev.destroy()
 }
 
-// The ovrHmd_GetLastError function has a bug, where it sends back an empty
-// string when there is no error, instead of NULL. Work around that.
 func (hmd *Hmd) GetLastError() *string {
-	if str := C.ovrHmd_GetLastError(hmd.hmdRef); str != nil && C.strlen(str) != 0 {
-		goStr := C.GoString(str)
-		return &goStr
-	}
-
-	return nil
+	return hmd.dev.lastError()
 }
 
 func (hmd *Hmd) GetEnabledCaps() uint {
-	return uint(C.ovrHmd_GetEnabledCaps(hmd.hmdRef))
+	return hmd.dev.enabledCaps()
 }
 
 func (hmd *Hmd) SetEnabledCaps(hmdCaps uint) {
-	C.ovrHmd_SetEnabledCaps(hmd.hmdRef, C.uint(hmdCaps))
+	hmd.dev.setEnabledCaps(hmdCaps)
 }
 
 // ****************************************************************************
@@ -618,15 +374,15 @@ func (hmd *Hmd) SetEnabledCaps(hmdCaps uint) {
 // ****************************************************************************
 
 func (hmd *Hmd) ConfigureTracking(supportedTrackingCaps uint, requiredTrackingCaps uint) bool {
-	return C.ovrHmd_ConfigureTracking(hmd.hmdRef, C.uint(supportedTrackingCaps), C.uint(requiredTrackingCaps)) == 1
+	return hmd.dev.configureTracking(supportedTrackingCaps, requiredTrackingCaps)
 }
 
 func (hmd *Hmd) RecenterPose() {
-	C.ovrHmd_RecenterPose(hmd.hmdRef)
+	hmd.dev.recenterPose()
 }
 
 func (hmd *Hmd) GetTrackingState(absTime float64) TrackingState {
-	return newTrackingState(C.ovrHmd_GetTrackingState(hmd.hmdRef, C.double(absTime)))
+	return hmd.dev.trackingState(absTime)
 }
 
 // ****************************************************************************
@@ -634,7 +390,7 @@ func (hmd *Hmd) GetTrackingState(absTime float64) TrackingState {
 // ****************************************************************************
 
 func (hmd *Hmd) GetFovTextureSize(eye EyeType, fov FovPort, pixelsPerDisplayPixel float32) Sizei {
-	return newSizei(C.ovrHmd_GetFovTextureSize(hmd.hmdRef, C.ovrEyeType(eye), fov.toC(), C.float(pixelsPerDisplayPixel)))
+	return hmd.dev.fovTextureSize(eye, fov, pixelsPerDisplayPixel)
 }
 
 // ****************************************************************************
@@ -642,11 +398,8 @@ func (hmd *Hmd) GetFovTextureSize(eye EyeType, fov FovPort, pixelsPerDisplayPixe
 // ****************************************************************************
 
 func (hmd *Hmd) ConfigureRendering(apiConfig *RenderAPIConfig, distortionCaps uint, eyeFovIn [2]FovPort) (*[2]EyeRenderDesc, error) {
-	_apiConfig := C.ovrRenderAPIConfig(*apiConfig)
-	_eyeFovIn := [2]C.ovrFovPort{eyeFovIn[0].toC(), eyeFovIn[1].toC()}
-	eyeRenderDescOut := [2]C.ovrEyeRenderDesc{}
-
-	if C.ovrHmd_ConfigureRendering(hmd.hmdRef, &_apiConfig, C.uint(distortionCaps), &_eyeFovIn[0], &eyeRenderDescOut[0]) == 0 {
+	eyeRenderDescOut, ok := hmd.dev.configureRendering(apiConfig, distortionCaps, eyeFovIn)
+	if !ok {
 		if lastError := hmd.GetLastError(); lastError != nil {
 			return nil, errors.New(*lastError)
 		}
@@ -654,27 +407,19 @@ func (hmd *Hmd) ConfigureRendering(apiConfig *RenderAPIConfig, distortionCaps ui
 		return nil, errors.New("An unknown error occured")
 	}
 
-	_eyeRenderDescOut := [2]EyeRenderDesc{
-		newEyeRenderDesc(eyeRenderDescOut[0]),
-		newEyeRenderDesc(eyeRenderDescOut[1]),
-	}
-
-	return &_eyeRenderDescOut, nil
+	return &eyeRenderDescOut, nil
 }
 
 func (hmd *Hmd) BeginFrame(frameIndex uint) FrameTiming {
-	return FrameTiming(C.ovrHmd_BeginFrame(hmd.hmdRef, C.uint(frameIndex)))
+	return hmd.dev.beginFrame(frameIndex)
 }
 
 func (hmd *Hmd) EndFrame(renderPose [2]Posef, eyeTexture [2]Texture) {
-	_renderPose := [2]C.ovrPosef{renderPose[0].toC(), renderPose[1].toC()}
-	_eyeTexture := [2]C.ovrTexture{eyeTexture[0].toC(), eyeTexture[1].toC()}
-
-	C.ovrHmd_EndFrame(hmd.hmdRef, &_renderPose[0], &_eyeTexture[0])
+	hmd.dev.endFrame(renderPose, eyeTexture)
 }
 
 func (hmd *Hmd) GetEyePose(eye EyeType) Posef {
-	return newPosef(C.ovrHmd_GetEyePose(hmd.hmdRef, C.ovrEyeType(eye)))
+	return hmd.dev.eyePose(eye)
 }
 
 // ****************************************************************************
@@ -682,20 +427,35 @@ func (hmd *Hmd) GetEyePose(eye EyeType) Posef {
 // ****************************************************************************
 
 func (hmd *Hmd) GetRenderDesc(eye EyeType, fov FovPort) EyeRenderDesc {
-	return newEyeRenderDesc(C.ovrHmd_GetRenderDesc(hmd.hmdRef, C.ovrEyeType(eye), fov.toC()))
+	return hmd.dev.renderDesc(eye, fov)
+}
+
+// Describes a vertex used for distortion rendering.
+type DistortionVertex struct {
+	ScreenPosNDC   Vector2f
+	TimeWarpFactor float32
+	VignetteFactor float32
+	TanEyeAnglesR  Vector2f
+	TanEyeAnglesG  Vector2f
+	TanEyeAnglesB  Vector2f
 }
 
-// type DistortionVertex C.ovrDistortionVertex
-type DistortionMesh C.ovrDistortionMesh
+// Describes a full set of distortion mesh data, filled in by
+// CreateDistortionMesh. The data is owned by Go, so Destroy() only releases
+// the references to it.
+type DistortionMesh struct {
+	VertexData []DistortionVertex
+	IndexData  []uint16
+}
 
 func (mesh *DistortionMesh) Destroy() {
-	C.ovrHmd_DestroyDistortionMesh((*C.ovrDistortionMesh)(unsafe.Pointer(mesh)))
+	mesh.VertexData = nil
+	mesh.IndexData = nil
 }
 
 func (hmd *Hmd) CreateDistortionMesh(eye EyeType, fov FovPort, distortionCaps uint) (*DistortionMesh, error) {
-	meshData := DistortionMesh{}
-
-	if C.ovrHmd_CreateDistortionMesh(hmd.hmdRef, C.ovrEyeType(eye), fov.toC(), C.uint(distortionCaps), (*C.ovrDistortionMesh)(unsafe.Pointer(&meshData))) == 0 {
+	meshData, ok := hmd.dev.createDistortionMesh(eye, fov, distortionCaps)
+	if !ok {
 		if lastError := hmd.GetLastError(); lastError != nil {
 			return nil, errors.New(*lastError)
 		}
@@ -703,37 +463,31 @@ func (hmd *Hmd) CreateDistortionMesh(eye EyeType, fov FovPort, distortionCaps ui
 		return nil, errors.New("An unknown error occured")
 	}
 
-	return &meshData, nil
+	return meshData, nil
 }
 
 func (hmd *Hmd) GetRenderScaleAndOffset(fov FovPort, textureSize Sizei, renderViewport Recti) [2]Vector2f {
-	uvScaleOffsetOut := [2]C.ovrVector2f{}
-	C.ovrHmd_GetRenderScaleAndOffset(fov.toC(), textureSize.toC(), renderViewport.toC(), &uvScaleOffsetOut[0])
-
-	return [2]Vector2f{newVector2f(uvScaleOffsetOut[0]), newVector2f(uvScaleOffsetOut[1])}
+	return currentBackend.renderScaleAndOffset(fov, textureSize, renderViewport)
 }
 
 func (hmd *Hmd) GetFrameTiming(frameIndex uint) FrameTiming {
-	return FrameTiming(C.ovrHmd_GetFrameTiming(hmd.hmdRef, C.uint(frameIndex)))
+	return hmd.dev.frameTiming(frameIndex)
 }
 
 func (hmd *Hmd) BeginFrameTiming(frameIndex uint) FrameTiming {
-	return FrameTiming(C.ovrHmd_BeginFrameTiming(hmd.hmdRef, C.uint(frameIndex)))
+	return hmd.dev.beginFrameTiming(frameIndex)
 }
 
 func (hmd *Hmd) EndFrameTiming() {
-	C.ovrHmd_EndFrameTiming(hmd.hmdRef)
+	hmd.dev.endFrameTiming()
 }
 
 func (hmd *Hmd) ResetFrameTiming(frameIndex uint) {
-	C.ovrHmd_ResetFrameTiming(hmd.hmdRef, C.uint(frameIndex))
+	hmd.dev.resetFrameTiming(frameIndex)
 }
 
 func (hmd *Hmd) GetEyeTimewarpMatrices(eye EyeType, renderPose Posef) [2]Matrix4f {
-	twmOut := [2]C.ovrMatrix4f{}
-	C.ovrHmd_GetEyeTimewarpMatrices(hmd.hmdRef, C.ovrEyeType(eye), renderPose.toC(), &twmOut[0])
-
-	return [2]Matrix4f{newMatrix4f(twmOut[0]), newMatrix4f(twmOut[1])}
+	return hmd.dev.eyeTimewarpMatrices(eye, renderPose)
 }
 
 // ****************************************************************************
@@ -741,19 +495,19 @@ func (hmd *Hmd) GetEyeTimewarpMatrices(eye EyeType, renderPose Posef) [2]Matrix4
 // ****************************************************************************
 
 func Matrix4f_Projection(fov FovPort, znear float32, zfar float32, rightHanded bool) Matrix4f {
-	return newMatrix4f(C.ovrMatrix4f_Projection(fov.toC(), C.float(znear), C.float(zfar), ovrBool(rightHanded)))
+	return currentBackend.projection(fov, znear, zfar, rightHanded)
 }
 
 func Matrix4f_OrthoSubProjection(projection Matrix4f, orthoScale Vector2f, orthoDistance float32, eyeViewAdjustX float32) Matrix4f {
-	return newMatrix4f(C.ovrMatrix4f_OrthoSubProjection(projection.toC(), orthoScale.toC(), C.float(orthoDistance), C.float(eyeViewAdjustX)))
+	return currentBackend.orthoSubProjection(projection, orthoScale, orthoDistance, eyeViewAdjustX)
 }
 
 func GetTimeInSeconds() float64 {
-	return float64(C.ovr_GetTimeInSeconds())
+	return currentBackend.timeInSeconds()
 }
 
 func WaitTillTime(absTime float64) float64 {
-	return float64(C.ovr_WaitTillTime(C.double(absTime)))
+	return currentBackend.waitTillTime(absTime)
 }
 
 // ****************************************************************************
@@ -761,23 +515,11 @@ func WaitTillTime(absTime float64) float64 {
 // ****************************************************************************
 
 func (hmd *Hmd) ProcessLatencyTest() (*[3]uint, bool) {
-	rgbColorOut := [3]C.uchar{}
-	if C.ovrHmd_ProcessLatencyTest(hmd.hmdRef, &rgbColorOut[0]) == 0 {
-		return nil, false
-	}
-
-	return &[3]uint{uint(rgbColorOut[0]), uint(rgbColorOut[1]), uint(rgbColorOut[2])}, true
+	return hmd.dev.processLatencyTest()
 }
 
-// The ovrHmd_GetLatencyTestResult function has a bug, where it sends back an
-// empty string when there is no error, instead of a NULL. Work around that.
 func (hmd *Hmd) GetLatencyTestResult() *string {
-	if str := C.ovrHmd_GetLatencyTestResult(hmd.hmdRef); str != nil && C.strlen(str) != 0 {
-		goStr := C.GoString(str)
-		return &goStr
-	}
-
-	return nil
+	return hmd.dev.latencyTestResult()
 }
 
 // ****************************************************************************
@@ -790,23 +532,13 @@ type HSWDisplayState struct {
 	DismissibleTime float64
 }
 
-func (state HSWDisplayState) toC() C.ovrHSWDisplayState {
-	return C.ovrHSWDisplayState{
-		Displayed:       ovrBool(state.Displayed),
-		StartTime:       C.double(state.StartTime),
-		DismissibleTime: C.double(state.DismissibleTime),
-	}
-}
-
 func (hmd *Hmd) GetHSWDisplayState() *HSWDisplayState {
-	hasWarningState := HSWDisplayState{}
-	C.ovrHmd_GetHSWDisplayState(hmd.hmdRef, (*C.ovrHSWDisplayState)(unsafe.Pointer(&hasWarningState)))
-
+	hasWarningState := hmd.dev.hswDisplayState()
 	return &hasWarningState
 }
 
 func (hmd *Hmd) DismissHSWDisplay() bool {
-	return C.ovrHmd_DismissHSWDisplay(hmd.hmdRef) == 1
+	return hmd.dev.dismissHSWDisplay()
 }
 
 // ****************************************************************************
@@ -814,85 +546,59 @@ func (hmd *Hmd) DismissHSWDisplay() bool {
 // ****************************************************************************
 
 const (
-	KEY_USER                 = C.OVR_KEY_USER
-	KEY_NAME                 = C.OVR_KEY_NAME
-	KEY_GENDER               = C.OVR_KEY_GENDER
-	KEY_PLAYER_HEIGHT        = C.OVR_KEY_PLAYER_HEIGHT
-	KEY_EYE_HEIGHT           = C.OVR_KEY_EYE_HEIGHT
-	KEY_IPD                  = C.OVR_KEY_IPD
-	KEY_NECK_TO_EYE_DISTANCE = C.OVR_KEY_NECK_TO_EYE_DISTANCE
-
-	DEFAULT_GENDER                 = C.OVR_DEFAULT_GENDER
-	DEFAULT_PLAYER_HEIGHT          = C.float(1.778)
-	DEFAULT_EYE_HEIGHT             = C.float(1.675)
-	DEFAULT_IPD                    = C.float(0.064)
-	DEFAULT_NECK_TO_EYE_HORIZONTAL = C.float(0.0805)
-	DEFAULT_NECK_TO_EYE_VERTICAL   = C.float(0.075)
-	DEFAULT_EYE_RELIEF_DIAL        = C.OVR_DEFAULT_EYE_RELIEF_DIAL
+	KEY_USER                 = "User"
+	KEY_NAME                 = "Name"
+	KEY_GENDER               = "Gender"
+	KEY_PLAYER_HEIGHT        = "PlayerHeight"
+	KEY_EYE_HEIGHT           = "EyeHeight"
+	KEY_IPD                  = "IPD"
+	KEY_NECK_TO_EYE_DISTANCE = "NeckEyeDistance"
+
+	DEFAULT_GENDER                 = "Unknown"
+	DEFAULT_PLAYER_HEIGHT          = float32(1.778)
+	DEFAULT_EYE_HEIGHT             = float32(1.675)
+	DEFAULT_IPD                    = float32(0.064)
+	DEFAULT_NECK_TO_EYE_HORIZONTAL = float32(0.0805)
+	DEFAULT_NECK_TO_EYE_VERTICAL   = float32(0.075)
+	DEFAULT_EYE_RELIEF_DIAL        = 3
 )
 
 func (hmd *Hmd) GetBool(propertyName string, defaultVal bool) bool {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	return C.ovrHmd_GetBool(hmd.hmdRef, _propertyName, ovrBool(defaultVal)) == 1
+	return hmd.dev.getBool(propertyName, defaultVal)
 }
 
 func (hmd *Hmd) SetBool(propertyName string, value bool) bool {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	return C.ovrHmd_SetBool(hmd.hmdRef, _propertyName, ovrBool(value)) == 1
+	return hmd.dev.setBool(propertyName, value)
 }
 
 func (hmd *Hmd) GetInt(propertyName string, defaultVal int) int {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	return int(C.ovrHmd_GetInt(hmd.hmdRef, _propertyName, C.int(defaultVal)))
+	return hmd.dev.getInt(propertyName, defaultVal)
 }
 
 func (hmd *Hmd) SetInt(propertyName string, value int) bool {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	return C.ovrHmd_SetInt(hmd.hmdRef, _propertyName, C.int(value)) == 1
+	return hmd.dev.setInt(propertyName, value)
 }
 
 func (hmd *Hmd) GetFloat(propertyName string, defaultVal float32) float32 {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	return float32(C.ovrHmd_GetFloat(hmd.hmdRef, _propertyName, C.float(defaultVal)))
+	return hmd.dev.getFloat(propertyName, defaultVal)
 }
 
 func (hmd *Hmd) SetFloat(propertyName string, value float32) bool {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	return C.ovrHmd_SetFloat(hmd.hmdRef, _propertyName, C.float(value)) == 1
+	return hmd.dev.setFloat(propertyName, value)
 }
 
 func (hmd *Hmd) GetFloatArray(propertyName string, values []float32, arraySize uint) uint {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	_values := C.float(values[0])
-	return uint(C.ovrHmd_GetFloatArray(hmd.hmdRef, _propertyName, &_values, C.uint(arraySize)))
+	return hmd.dev.getFloatArray(propertyName, values, arraySize)
 }
 
 func (hmd *Hmd) SetFloatArray(propertyName string, values []float32, arraySize uint) bool {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	_values := C.float(values[0])
-	return C.ovrHmd_SetFloatArray(hmd.hmdRef, _propertyName, &_values, C.uint(arraySize)) == 1
+	return hmd.dev.setFloatArray(propertyName, values, arraySize)
 }
 
 func (hmd *Hmd) GetString(propertyName, defaultVal string) string {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	_defaultVal := C.CString(defaultVal)
-	defer C.free(unsafe.Pointer(_defaultVal))
-	return C.GoString(C.ovrHmd_GetString(hmd.hmdRef, _propertyName, _defaultVal))
+	return hmd.dev.getString(propertyName, defaultVal)
 }
 
 func (hmd *Hmd) SetString(propertyName, value string) bool {
-	_propertyName := C.CString(propertyName)
-	defer C.free(unsafe.Pointer(_propertyName))
-	_value := C.CString(value)
-	defer C.free(unsafe.Pointer(_value))
-	return C.ovrHmd_SetString(hmd.hmdRef, _propertyName, _value) == 1
+	return hmd.dev.setString(propertyName, value)
 }
@@ -1,15 +1,13 @@
 package ovr
 
-/*
-#include <OVR_CAPI_GL.h>
-*/
-import "C"
+// The library Initialize() loads, unless LibraryPath is changed.
+const defaultLibraryPath = "libovr.dylib"
 
 // Used to configure slave GL rendering (i.e. for devices created externally).
 type GLConfigData struct {
 	Header RenderAPIConfigHeader
 }
 
-func (configData GLConfigData) toC() C.ovrGLConfigData {
-	return C.ovrGLConfigData{Header: configData.Header.toC()}
+func (configData GLConfigData) platformData() [8]uintptr {
+	return [8]uintptr{}
 }
@@ -1,8 +1,16 @@
 package ovr
 
+// The library Initialize() loads, unless LibraryPath is changed.
+const defaultLibraryPath = "libovr.so"
+
 // Used to configure slave GL rendering (i.e. for devices created externally).
+// Disp is a pointer to the X11 Display and Win the X11 Window to render to.
 type GLConfigData struct {
 	Header RenderAPIConfigHeader
-	Disp   *C._XDisplay
-	Win    C.Window
+	Disp   uintptr
+	Win    uintptr
+}
+
+func (configData GLConfigData) platformData() [8]uintptr {
+	return [8]uintptr{configData.Disp, configData.Win}
 }
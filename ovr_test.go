@@ -29,7 +29,9 @@ func destroyAndShutdown(hmd *Hmd) {
 // ****************************************************************************
 
 func TestInitializeRenderingShimAndShutdown(t *testing.T) {
-	InitializeRenderingShim()
+	if err := InitializeRenderingShim(); err != nil {
+		t.Errorf("Expected InitializeRenderingShim() to succeed, instead of '%s'", err)
+	}
 	Shutdown()
 }
 
@@ -57,7 +59,7 @@ func TestGetLastError(t *testing.T) {
 	defer destroyAndShutdown(hmd)
 
 	if errStr := hmd.GetLastError(); errStr != nil {
-		t.Errorf("Expected GetLastError() to return nil, instead of '%s'", errStr)
+		t.Errorf("Expected GetLastError() to return nil, instead of '%s'", *errStr)
 	}
 
 	// XXX: Add a case here where an error is triggered and read back.
@@ -120,7 +122,7 @@ func TestGetFovTextureSize(t *testing.T) {
 	textureSize := hmd.GetFovTextureSize(Eye_Left, hmd.DefaultEyeFov[0], 1.0)
 
 	if textureSize.W != 1182 || textureSize.H != 1461 {
-		t.Error("Expected a textureSize of 1182x1461, instead of %dx%d", textureSize.W, textureSize.H)
+		t.Errorf("Expected a textureSize of 1182x1461, instead of %dx%d", textureSize.W, textureSize.H)
 	}
 }
 
@@ -1,12 +1,30 @@
 package ovr
 
+import "syscall"
+
+// The library Initialize() loads, unless LibraryPath is changed.
+const defaultLibraryPath = "libovr.dll"
+
 // Used to configure slave GL rendering (i.e. for devices created externally).
 type GLConfigData struct {
 	Header RenderAPIConfigHeader
-	Window C.HWND
-	DC     C.HDC
+	Window syscall.Handle
+	DC     syscall.Handle
+}
+
+func (configData GLConfigData) platformData() [8]uintptr {
+	return [8]uintptr{uintptr(configData.Window), uintptr(configData.DC)}
+}
+
+// Implemented by devices that can mirror their output to a window.
+type windowAttacher interface {
+	attachToWindow(hwnd syscall.Handle) bool
 }
 
 func (hmd *Hmd) AttachToWindow(hwnd syscall.Handle) bool {
-	return C.char(C.ovrHmd_AttachToWindow(hmd.hmdRef, uintptr(hwnd), nil, nil)) == 1
+	if attacher, ok := baseDevice(hmd.dev).(windowAttacher); ok {
+		return attacher.attachToWindow(hwnd)
+	}
+
+	return false
 }
@@ -0,0 +1,350 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ***************************** [ Pose smoothing ] ***************************
+// ****************************************************************************
+
+// A PoseFilter smooths the poses of the head, for example for a gaze cursor or
+// for head-locked UI, which would otherwise jitter with the noise of the
+// tracking. The filters are driven by the TimeInSeconds of the pose states,
+// so they behave the same at any frame rate.
+//
+// RecenterPose() makes the pose jump. Call Reset() after it, so the filter
+// starts over from the recentered pose instead of smoothing the jump.
+type PoseFilter interface {
+	// Update takes the next pose state, and returns the smoothed pose.
+	Update(poseState PoseStatef) Posef
+
+	// Reset makes the filter start over with the next pose state.
+	Reset()
+}
+
+// Returns the rotation vector of q, its axis scaled by its angle, along the
+// shortest arc.
+func rotationVector(q Quatf) Vector3f {
+	if q.W < 0 {
+		q = Quatf{-q.X, -q.Y, -q.Z, -q.W}
+	}
+
+	axis, angle := q.AxisAngle()
+	return vectorScale(axis, angle)
+}
+
+// Returns the rotation around the rotation vector v, by its length.
+func quatfFromRotationVector(v Vector3f) Quatf {
+	return QuatfFromAxisAngle(v, vectorLength(v))
+}
+
+// ****************************************************************************
+// ****************************** [ One-Euro filter ] *************************
+// ****************************************************************************
+
+// Returns the weight of the new value of a low-pass filter with the cutoff
+// frequency in Hz, after dt seconds.
+func oneEuroAlpha(cutoff, dt float32) float32 {
+	tau := 1 / (2 * math.Pi * cutoff)
+	return 1 / (1 + tau/dt)
+}
+
+// A OneEuroVectorFilter is the One-Euro filter of Casiez et al. for a
+// Vector3f: a low-pass filter whose cutoff frequency rises with the speed of
+// the value, so it removes the jitter when the value is slow, without lagging
+// behind when it is fast.
+type OneEuroVectorFilter struct {
+	// The cutoff frequency in Hz when the value doesn't move. Lower it to
+	// remove more jitter.
+	MinCutoff float32
+
+	// How much the cutoff frequency rises with the speed, in Hz per unit per
+	// second. Raise it to lag less during fast motion.
+	Beta float32
+
+	// The cutoff frequency in Hz of the low-pass filter on the speed.
+	DerivativeCutoff float32
+
+	started    bool
+	lastTime   float64
+	last       Vector3f
+	value      Vector3f
+	derivative Vector3f
+}
+
+func NewOneEuroVectorFilter(minCutoff, beta float32) *OneEuroVectorFilter {
+	return &OneEuroVectorFilter{MinCutoff: minCutoff, Beta: beta, DerivativeCutoff: 1}
+}
+
+// Update takes the value v at time in seconds, and returns the filtered
+// value.
+func (filter *OneEuroVectorFilter) Update(v Vector3f, time float64) Vector3f {
+	if !filter.started {
+		filter.started = true
+		filter.lastTime = time
+		filter.last = v
+		filter.value = v
+		filter.derivative = Vector3f{}
+
+		return v
+	}
+
+	dt := float32(time - filter.lastTime)
+	if dt <= 0 {
+		return filter.value
+	}
+	filter.lastTime = time
+
+	// The speed is taken from the unfiltered values, so the lag of the
+	// filtered one doesn't add to it.
+	rate := vectorScale(vectorSub(v, filter.last), 1/dt)
+	filter.last = v
+	filter.derivative = vectorLerp(filter.derivative, rate, oneEuroAlpha(filter.DerivativeCutoff, dt))
+
+	cutoff := filter.MinCutoff + filter.Beta*vectorLength(filter.derivative)
+	filter.value = vectorLerp(filter.value, v, oneEuroAlpha(cutoff, dt))
+
+	return filter.value
+}
+
+// Reset makes the filter start over with the next value.
+func (filter *OneEuroVectorFilter) Reset() {
+	filter.started = false
+}
+
+// A OneEuroQuatFilter is the One-Euro filter for a rotation. It filters on the
+// sphere of rotations, along the shortest arc, so the result stays a rotation,
+// and the speed is the angular speed, in rad/s.
+type OneEuroQuatFilter struct {
+	// The cutoff frequency in Hz when the rotation doesn't change.
+	MinCutoff float32
+
+	// How much the cutoff frequency rises with the angular speed, in Hz per
+	// rad/s.
+	Beta float32
+
+	// The cutoff frequency in Hz of the low-pass filter on the angular
+	// velocity.
+	DerivativeCutoff float32
+
+	started    bool
+	lastTime   float64
+	last       Quatf
+	value      Quatf
+	derivative Vector3f
+}
+
+func NewOneEuroQuatFilter(minCutoff, beta float32) *OneEuroQuatFilter {
+	return &OneEuroQuatFilter{MinCutoff: minCutoff, Beta: beta, DerivativeCutoff: 1}
+}
+
+// Update takes the rotation q at time in seconds, and returns the filtered
+// rotation.
+func (filter *OneEuroQuatFilter) Update(q Quatf, time float64) Quatf {
+	if !filter.started {
+		filter.started = true
+		filter.lastTime = time
+		filter.last = q
+		filter.value = q.Normalize()
+		filter.derivative = Vector3f{}
+
+		return filter.value
+	}
+
+	dt := float32(time - filter.lastTime)
+	if dt <= 0 {
+		return filter.value
+	}
+	filter.lastTime = time
+
+	// The angular velocity from the last unfiltered rotation to q, in the
+	// frame of the head.
+	rate := vectorScale(rotationVector(filter.last.Conjugate().Mul(q)), 1/dt)
+	filter.last = q
+	filter.derivative = vectorLerp(filter.derivative, rate, oneEuroAlpha(filter.DerivativeCutoff, dt))
+
+	cutoff := filter.MinCutoff + filter.Beta*vectorLength(filter.derivative)
+	filter.value = filter.value.Slerp(q, oneEuroAlpha(cutoff, dt))
+
+	return filter.value
+}
+
+// Reset makes the filter start over with the next rotation.
+func (filter *OneEuroQuatFilter) Reset() {
+	filter.started = false
+}
+
+// A OneEuroPoseFilter filters the position and the orientation of a pose with
+// One-Euro filters. Tune them through Position and Orientation.
+type OneEuroPoseFilter struct {
+	Position    *OneEuroVectorFilter
+	Orientation *OneEuroQuatFilter
+}
+
+// NewOneEuroPoseFilter returns a filter that suits a gaze cursor: the
+// position has a MinCutoff of 1 Hz and a Beta of 5 Hz per m/s, and the
+// orientation a MinCutoff of 1 Hz and a Beta of 1 Hz per rad/s.
+func NewOneEuroPoseFilter() *OneEuroPoseFilter {
+	return &OneEuroPoseFilter{
+		Position:    NewOneEuroVectorFilter(1, 5),
+		Orientation: NewOneEuroQuatFilter(1, 1),
+	}
+}
+
+func (filter *OneEuroPoseFilter) Update(poseState PoseStatef) Posef {
+	return Posef{
+		Orientation: filter.Orientation.Update(poseState.ThePose.Orientation, poseState.TimeInSeconds),
+		Position:    filter.Position.Update(poseState.ThePose.Position, poseState.TimeInSeconds),
+	}
+}
+
+func (filter *OneEuroPoseFilter) Reset() {
+	filter.Position.Reset()
+	filter.Orientation.Reset()
+}
+
+// ****************************************************************************
+// ****************************** [ Spring filter ] ***************************
+// ****************************************************************************
+
+// Moves the offset x from the target, and its velocity v, dt seconds along a
+// critically damped spring with the angular frequency omega. The solution is
+// exact, so while the target holds still, the result doesn't depend on the
+// steps it is computed in.
+func springStep(x, v Vector3f, omega, dt float32) (Vector3f, Vector3f) {
+	decay := float32(math.Exp(-float64(omega * dt)))
+
+	// x(t) = (x0 + (v0 + ω·x0)·t)·e^(-ωt)
+	// v(t) = (v0 - ω·(v0 + ω·x0)·t)·e^(-ωt)
+	a := vectorScale(vectorAdd(v, vectorScale(x, omega)), dt)
+	x = vectorScale(vectorAdd(x, a), decay)
+	v = vectorScale(vectorSub(v, vectorScale(a, omega)), decay)
+
+	return x, v
+}
+
+// A SpringVectorFilter pulls its value towards the target Vector3f with a
+// critically damped spring, the fastest spring that doesn't overshoot. It
+// lags behind the target by about SmoothTime seconds.
+type SpringVectorFilter struct {
+	// The time in seconds the value takes to catch up with the target.
+	SmoothTime float32
+
+	started  bool
+	lastTime float64
+	value    Vector3f
+	velocity Vector3f
+}
+
+func NewSpringVectorFilter(smoothTime float32) *SpringVectorFilter {
+	return &SpringVectorFilter{SmoothTime: smoothTime}
+}
+
+// Update moves the value towards the target v at time in seconds, and returns
+// it.
+func (filter *SpringVectorFilter) Update(v Vector3f, time float64) Vector3f {
+	if !filter.started {
+		filter.started = true
+		filter.lastTime = time
+		filter.value = v
+		filter.velocity = Vector3f{}
+
+		return v
+	}
+
+	dt := float32(time - filter.lastTime)
+	if dt <= 0 || filter.SmoothTime <= 0 {
+		return filter.value
+	}
+	filter.lastTime = time
+
+	var offset Vector3f
+	offset, filter.velocity = springStep(vectorSub(filter.value, v), filter.velocity, 2/filter.SmoothTime, dt)
+	filter.value = vectorAdd(v, offset)
+
+	return filter.value
+}
+
+// Reset makes the filter start over with the next target.
+func (filter *SpringVectorFilter) Reset() {
+	filter.started = false
+}
+
+// A SpringQuatFilter pulls its rotation towards the target rotation with a
+// critically damped spring. The spring works on the rotation vector from the
+// target to the value, so the result stays a rotation and turns along the
+// shortest arc.
+type SpringQuatFilter struct {
+	// The time in seconds the rotation takes to catch up with the target.
+	SmoothTime float32
+
+	started  bool
+	lastTime float64
+	value    Quatf
+	velocity Vector3f
+}
+
+func NewSpringQuatFilter(smoothTime float32) *SpringQuatFilter {
+	return &SpringQuatFilter{SmoothTime: smoothTime}
+}
+
+// Update turns the rotation towards the target q at time in seconds, and
+// returns it.
+func (filter *SpringQuatFilter) Update(q Quatf, time float64) Quatf {
+	if !filter.started {
+		filter.started = true
+		filter.lastTime = time
+		filter.value = q.Normalize()
+		filter.velocity = Vector3f{}
+
+		return filter.value
+	}
+
+	dt := float32(time - filter.lastTime)
+	if dt <= 0 || filter.SmoothTime <= 0 {
+		return filter.value
+	}
+	filter.lastTime = time
+
+	// The offset is the rotation from the target to the value, in the frame of
+	// the target.
+	var offset Vector3f
+	offset, filter.velocity = springStep(rotationVector(q.Conjugate().Mul(filter.value)), filter.velocity, 2/filter.SmoothTime, dt)
+	filter.value = q.Mul(quatfFromRotationVector(offset)).Normalize()
+
+	return filter.value
+}
+
+// Reset makes the filter start over with the next target.
+func (filter *SpringQuatFilter) Reset() {
+	filter.started = false
+}
+
+// A SpringPoseFilter pulls the position and the orientation of a pose towards
+// the pose of the head with critically damped springs. Unlike the One-Euro
+// filter, it lags the same at every speed, which makes head-locked UI float
+// smoothly behind the gaze.
+type SpringPoseFilter struct {
+	Position    *SpringVectorFilter
+	Orientation *SpringQuatFilter
+}
+
+// NewSpringPoseFilter returns a filter whose position and orientation catch up
+// with the head in about smoothTime seconds.
+func NewSpringPoseFilter(smoothTime float32) *SpringPoseFilter {
+	return &SpringPoseFilter{
+		Position:    NewSpringVectorFilter(smoothTime),
+		Orientation: NewSpringQuatFilter(smoothTime),
+	}
+}
+
+func (filter *SpringPoseFilter) Update(poseState PoseStatef) Posef {
+	return Posef{
+		Orientation: filter.Orientation.Update(poseState.ThePose.Orientation, poseState.TimeInSeconds),
+		Position:    filter.Position.Update(poseState.ThePose.Position, poseState.TimeInSeconds),
+	}
+}
+
+func (filter *SpringPoseFilter) Reset() {
+	filter.Position.Reset()
+	filter.Orientation.Reset()
+}
@@ -0,0 +1,170 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+// Returns the largest angle between the rotations q and r.
+func quatfAngle(q, r Quatf) float64 {
+	return 2 * math.Acos(math.Min(math.Abs(float64(q.Dot(r))), 1))
+}
+
+func TestOneEuroVectorFilter(t *testing.T) {
+	filter := NewOneEuroVectorFilter(1, 5)
+
+	// Jitter of a millimeter around a point that doesn't move.
+	maxJitter := float32(0)
+	for i := 0; i <= 2000; i++ {
+		jitter := Vector3f{X: 0.001 * float32(i%2*2-1)}
+		result := filter.Update(vectorAdd(Vector3f{0, 1.6, 0}, jitter), float64(i)/1000)
+
+		if i >= 1000 {
+			maxJitter = max(maxJitter, vectorLength(vectorSub(result, Vector3f{0, 1.6, 0})))
+		}
+	}
+
+	if maxJitter > 0.0001 {
+		t.Errorf("Expected the jitter to be removed, instead of %f", maxJitter)
+	}
+
+	// Moving at 1 m/s, the cutoff rises to 6 Hz, so the lag is 1/(2π·6) m.
+	lag := func(beta float32) float32 {
+		filter := NewOneEuroVectorFilter(1, beta)
+
+		result := Vector3f{}
+		for i := 0; i <= 3000; i++ {
+			result = filter.Update(Vector3f{X: float32(i) / 1000}, float64(i)/1000)
+		}
+
+		return 3 - result.X
+	}
+
+	if fast, slow := lag(5), lag(0); !approxFloat(1/(2*math.Pi*6), fast, 0.002) || !approxFloat(1/(2*math.Pi), slow, 0.002) {
+		t.Errorf("Expected a lag of %f m with Beta, and %f m without, instead of %f and %f", 1/(2*math.Pi*6), 1/(2*math.Pi), fast, slow)
+	}
+
+	filter.Reset()
+	if result := filter.Update(Vector3f{1, 2, 3}, 10); result != (Vector3f{1, 2, 3}) {
+		t.Errorf("Expected the filter to start over after Reset(), instead of %v", result)
+	}
+}
+
+func TestOneEuroQuatFilter(t *testing.T) {
+	filter := NewOneEuroQuatFilter(1, 1)
+	axis := vectorNormalize(Vector3f{1, 1, 0})
+
+	// Turning at 2 rad/s, the cutoff rises to 3 Hz.
+	for i := 0; i <= 3000; i++ {
+		angle := 2 * float32(i) / 1000
+		actual := QuatfFromAxisAngle(axis, angle)
+		result := filter.Update(actual, float64(i)/1000)
+
+		if !approxFloat(1, result.Length(), 1e-5) {
+			t.Fatalf("Expected a normalized rotation instead of %v", result)
+		}
+
+		if i == 3000 {
+			exp := QuatfFromAxisAngle(axis, angle-float32(2/(2*math.Pi*3)))
+			if !approxQuatf(exp, result, 1e-3) {
+				t.Errorf("Expected the rotation to lag behind around the same axis, at %v instead of %v", exp, result)
+			}
+		}
+	}
+
+	// Jitter of a milliradian around a rotation that doesn't move.
+	filter.Reset()
+	orientation := QuatfFromAxisAngle(Vector3f{Y: 1}, 2.5)
+	maxError := 0.0
+
+	for i := 0; i <= 2000; i++ {
+		jitter := QuatfFromAxisAngle(Vector3f{1, float32(i % 3), 0}, 0.001*float32(i%2*2-1))
+		result := filter.Update(orientation.Mul(jitter), 10+float64(i)/1000)
+
+		if i >= 1000 {
+			maxError = math.Max(maxError, quatfAngle(orientation, result))
+		}
+	}
+
+	if maxError > 0.0002 {
+		t.Errorf("Expected the jitter to be removed, instead of %f", maxError)
+	}
+}
+
+func TestSpringVectorFilter(t *testing.T) {
+	// The spring is solved exactly, so the step size doesn't matter.
+	step := func(dt float64) Vector3f {
+		filter := NewSpringVectorFilter(0.2)
+		filter.Update(Vector3f{}, 0)
+
+		result := Vector3f{}
+		for tm := dt; tm <= 0.5+1e-9; tm += dt {
+			result = filter.Update(Vector3f{X: 1}, tm)
+
+			if result.X > 1 {
+				t.Fatalf("Expected the spring not to overshoot, instead of %v at %f", result, tm)
+			}
+		}
+
+		return result
+	}
+
+	fine, coarse := step(0.001), step(0.05)
+	if !approxVector3f(fine, coarse, 1e-5) {
+		t.Errorf("Expected the same result with any step, instead of %v and %v", fine, coarse)
+	}
+
+	// x(t) = 1 - (1 + ωt)·e^(-ωt), with ω = 10.
+	if exp := float32(1 - 6*math.Exp(-5)); !approxFloat(exp, fine.X, 1e-5) {
+		t.Errorf("Expected %f after half a second instead of %f", exp, fine.X)
+	}
+}
+
+func TestSpringQuatFilter(t *testing.T) {
+	filter := NewSpringQuatFilter(0.1)
+	filter.Update(Quatf{W: 1}, 0)
+
+	target := QuatfFromAxisAngle(Vector3f{0, 1, 1}, 3)
+	last := math.Pi
+
+	for i := 1; i <= 1000; i++ {
+		result := filter.Update(target, float64(i)/1000)
+
+		// Close to the target, the angle is lost in float rounding.
+		remaining := quatfAngle(target, result)
+		if (remaining > 0.01 && remaining > last) || !approxFloat(1, result.Length(), 1e-5) {
+			t.Fatalf("Expected the rotation to turn steadily towards the target, instead of %v at %d ms", result, i)
+		}
+		last = remaining
+
+		if axis, _ := target.Conjugate().Mul(result).AxisAngle(); remaining > 0.01 && !approxVector3f(vectorNormalize(Vector3f{0, 1, 1}), axis, 1e-3) && !approxVector3f(vectorNormalize(Vector3f{0, -1, -1}), axis, 1e-3) {
+			t.Fatalf("Expected the rotation to turn along the shortest arc, instead of around %v", axis)
+		}
+	}
+
+	if last > 0.002 {
+		t.Errorf("Expected the rotation to catch up with the target, instead of %f radians away", last)
+	}
+}
+
+func TestPoseFilterRecenter(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+	hmd.Simulator().SetMotionScript(testMotionScript(), 0)
+
+	for _, filter := range []PoseFilter{NewOneEuroPoseFilter(), NewSpringPoseFilter(0.1)} {
+		for i := 0; i <= 1000; i++ {
+			filter.Update(hmd.GetTrackingState(float64(i) / 1000).HeadPose)
+		}
+
+		hmd.RecenterPose()
+		filter.Reset()
+
+		state := hmd.GetTrackingState(1.001).HeadPose
+		if result := filter.Update(state); !approxQuatf(state.ThePose.Orientation, result.Orientation, 1e-6) || result.Position != state.ThePose.Position {
+			t.Errorf("Expected the filter to start over from the recentered pose %v, instead of %v", state.ThePose, result)
+		}
+	}
+}
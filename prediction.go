@@ -0,0 +1,42 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ***************************** [ Pose prediction ] **************************
+// ****************************************************************************
+
+// The longest time Predict() extrapolates a pose over, in either direction.
+// Beyond it, the accelerations make the prediction diverge quickly.
+const MaxPredictionSeconds = 0.1
+
+// Predict returns the pose state dt seconds later, assuming the accelerations
+// stay constant. libOVR 0.4 assumes constant velocities instead, so this
+// predicts differently when the head speeds up or slows down. The angular
+// velocity and acceleration are in the frame of the head, as in the tracking
+// state. dt is limited to MaxPredictionSeconds, and may be negative to go back
+// in time.
+//
+// This doesn't need libOVR, so it can predict recorded or remote poses too.
+func (poseState PoseStatef) Predict(dt float64) PoseStatef {
+	dt = math.Max(-MaxPredictionSeconds, math.Min(dt, MaxPredictionSeconds))
+	t := float32(dt)
+
+	predicted := poseState
+	predicted.TimeInSeconds += dt
+
+	// p + v·t + a·t²/2, and v + a·t.
+	predicted.ThePose.Position = vectorAdd(poseState.ThePose.Position,
+		vectorAdd(vectorScale(poseState.LinearVelocity, t), vectorScale(poseState.LinearAcceleration, t*t/2)))
+	predicted.LinearVelocity = vectorAdd(poseState.LinearVelocity, vectorScale(poseState.LinearAcceleration, t))
+
+	// The head turns around the rotation vector ω·t + α·t²/2, in its own
+	// frame.
+	rotation := vectorAdd(vectorScale(poseState.AngularVelocity, t), vectorScale(poseState.AngularAcceleration, t*t/2))
+	if angle := vectorLength(rotation); angle > 0 {
+		predicted.ThePose.Orientation = poseState.ThePose.Orientation.Mul(QuatfFromAxisAngle(rotation, angle)).Normalize()
+	}
+	predicted.AngularVelocity = vectorAdd(poseState.AngularVelocity, vectorScale(poseState.AngularAcceleration, t))
+
+	return predicted
+}
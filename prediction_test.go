@@ -0,0 +1,83 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredictConstantMotion(t *testing.T) {
+	poseState := PoseStatef{
+		ThePose: Posef{
+			Orientation: QuatfFromAxisAngle(Vector3f{X: 1}, 0.3),
+			Position:    Vector3f{0, 1.6, 0},
+		},
+		AngularVelocity:    Vector3f{Y: 1},
+		LinearVelocity:     Vector3f{X: 0.5},
+		LinearAcceleration: Vector3f{Y: -2},
+		TimeInSeconds:      10,
+	}
+
+	predicted := poseState.Predict(0.05)
+
+	if predicted.TimeInSeconds != 10.05 {
+		t.Errorf("Expected the prediction at 10.05 seconds instead of %f", predicted.TimeInSeconds)
+	}
+
+	expPosition := Vector3f{0.025, 1.6 - 0.0025, 0}
+	if !approxVector3f(expPosition, predicted.ThePose.Position, 1e-6) {
+		t.Errorf("Expected the position %v instead of %v", expPosition, predicted.ThePose.Position)
+	}
+
+	if exp := (Vector3f{0.5, -0.1, 0}); !approxVector3f(exp, predicted.LinearVelocity, 1e-6) {
+		t.Errorf("Expected the linear velocity %v instead of %v", exp, predicted.LinearVelocity)
+	}
+
+	// The angular velocity is in the frame of the head, so the head turns
+	// around its own tilted Y axis.
+	expOrientation := poseState.ThePose.Orientation.Mul(QuatfFromAxisAngle(Vector3f{Y: 1}, 0.05))
+	if !approxQuatf(expOrientation, predicted.ThePose.Orientation, 1e-6) {
+		t.Errorf("Expected the orientation %v instead of %v", expOrientation, predicted.ThePose.Orientation)
+	}
+
+	if result := poseState.Predict(0); result != poseState {
+		t.Errorf("Expected no change without time, instead of %v", result)
+	}
+}
+
+func TestPredictMatchesMotionScript(t *testing.T) {
+	script := testMotionScript()
+
+	for _, tm := range []float64{0.3, 1.7, 2.9, 5.25, 7.8} {
+		for _, dt := range []float64{0.005, 0.02, -0.02} {
+			predicted := script.PoseState(tm).Predict(dt)
+			exp := script.PoseState(tm + dt)
+
+			// The error of the prediction grows with the jerk, as dt³.
+			delta := float32(math.Max(100*math.Abs(dt*dt*dt), 1e-5))
+
+			if !approxVector3f(exp.ThePose.Position, predicted.ThePose.Position, delta) {
+				t.Errorf("Expected the position %v at %f+%f instead of %v", exp.ThePose.Position, tm, dt, predicted.ThePose.Position)
+			}
+
+			if !approxQuatf(exp.ThePose.Orientation, predicted.ThePose.Orientation, delta) {
+				t.Errorf("Expected the orientation %v at %f+%f instead of %v", exp.ThePose.Orientation, tm, dt, predicted.ThePose.Orientation)
+			}
+		}
+	}
+}
+
+func TestPredictIsCapped(t *testing.T) {
+	poseState := PoseStatef{
+		ThePose:         Posef{Orientation: Quatf{W: 1}},
+		AngularVelocity: Vector3f{Y: 2},
+		LinearVelocity:  Vector3f{Z: -1},
+	}
+
+	if capped, exp := poseState.Predict(5), poseState.Predict(MaxPredictionSeconds); capped != exp {
+		t.Errorf("Expected the prediction to stop at %f seconds, instead of %v", MaxPredictionSeconds, capped)
+	}
+
+	if capped, exp := poseState.Predict(-5), poseState.Predict(-MaxPredictionSeconds); capped != exp {
+		t.Errorf("Expected the prediction to stop at %f seconds, instead of %v", -MaxPredictionSeconds, capped)
+	}
+}
@@ -0,0 +1,144 @@
+package ovr
+
+// Pure-Go versions of the stateless math functions of libOVR. They are used by
+// the backends that don't have the C-API available, and by programs that want
+// projections libOVR doesn't make.
+
+// A scale and offset that map tangent of angle units to NDC space.
+type scaleAndOffset2D struct {
+	Scale  Vector2f
+	Offset Vector2f
+}
+
+func ndcScaleAndOffsetFromFov(fov FovPort) scaleAndOffset2D {
+	projXScale := 2.0 / (fov.LeftTan + fov.RightTan)
+	projXOffset := (fov.LeftTan - fov.RightTan) * projXScale * 0.5
+	projYScale := 2.0 / (fov.UpTan + fov.DownTan)
+	projYOffset := (fov.UpTan - fov.DownTan) * projYScale * 0.5
+
+	return scaleAndOffset2D{
+		Scale:  Vector2f{projXScale, projYScale},
+		Offset: Vector2f{projXOffset, projYOffset},
+	}
+}
+
+func renderScaleAndOffset(fov FovPort, textureSize Sizei, renderViewport Recti) [2]Vector2f {
+	eyeToSourceNDC := ndcScaleAndOffsetFromFov(fov)
+
+	// Scale [-1,1] to [0,1] and then to the viewport on the render target.
+	scale := Vector2f{
+		float32(renderViewport.Size.W) / float32(textureSize.W),
+		float32(renderViewport.Size.H) / float32(textureSize.H),
+	}
+	offset := Vector2f{
+		float32(renderViewport.Pos.X) / float32(textureSize.W),
+		float32(renderViewport.Pos.Y) / float32(textureSize.H),
+	}
+
+	return [2]Vector2f{
+		{
+			eyeToSourceNDC.Scale.X * 0.5 * scale.X,
+			eyeToSourceNDC.Scale.Y * 0.5 * scale.Y,
+		},
+		{
+			(eyeToSourceNDC.Offset.X*0.5+0.5)*scale.X + offset.X,
+			(eyeToSourceNDC.Offset.Y*0.5+0.5)*scale.Y + offset.Y,
+		},
+	}
+}
+
+// Modifiers of the projection matrices Projection() returns. They can be
+// combined.
+type ProjectionModifier uint
+
+const (
+	Projection_None = 0x00
+
+	// Use a right-handed coordinate system, like libOVR does, where the camera
+	// looks down -Z.
+	Projection_RightHanded = 0x01
+
+	// Map the far plane to the smallest depth and the near plane to the
+	// largest, instead of the other way around. Together with a floating
+	// point depth buffer, reversed-Z spreads the depth precision evenly over
+	// the distance, which avoids z-fighting in large scenes.
+	Projection_FarLessThanNear = 0x02
+
+	// Put the far plane at infinity. zfar is ignored.
+	Projection_FarClipAtInfinity = 0x04
+
+	// Map depth to [-1, 1] like OpenGL does by default, instead of [0, 1] like
+	// Direct3D, Vulkan, and OpenGL with glClipControl(GL_ZERO_TO_ONE) do. The
+	// projection of libOVR 0.4 maps depth to [0, 1].
+	Projection_ClipRangeOpenGL = 0x08
+)
+
+// Projection is the pure-Go version of Matrix4f_Projection(), which doesn't
+// need libOVR. Without modifiers other than Projection_RightHanded, its result
+// matches the one of libOVR.
+func Projection(fov FovPort, znear float32, zfar float32, modifier ProjectionModifier) Matrix4f {
+	scaleAndOffset := ndcScaleAndOffsetFromFov(fov)
+
+	handednessScale := float32(1.0)
+	if modifier&Projection_RightHanded != 0 {
+		handednessScale = -1.0
+	}
+
+	// The depths the near and far plane are mapped to.
+	nearDepth, farDepth := float32(0.0), float32(1.0)
+	if modifier&Projection_ClipRangeOpenGL != 0 {
+		nearDepth = -1.0
+	}
+	if modifier&Projection_FarLessThanNear != 0 {
+		nearDepth, farDepth = farDepth, nearDepth
+	}
+
+	// The depth is (a·d + b) / d at a distance d in front of the camera.
+	var a, b float32
+	if modifier&Projection_FarClipAtInfinity != 0 {
+		a = farDepth
+		b = (nearDepth - farDepth) * znear
+	} else {
+		a = (farDepth*zfar - nearDepth*znear) / (zfar - znear)
+		b = (nearDepth - farDepth) * znear * zfar / (zfar - znear)
+	}
+
+	m := Matrix4f{}
+	m.M[0][0] = scaleAndOffset.Scale.X
+	m.M[0][2] = handednessScale * scaleAndOffset.Offset.X
+	m.M[1][1] = scaleAndOffset.Scale.Y
+	m.M[1][2] = handednessScale * -scaleAndOffset.Offset.Y
+	m.M[2][2] = handednessScale * a
+	m.M[2][3] = b
+	m.M[3][2] = handednessScale
+
+	return m
+}
+
+func projection(fov FovPort, znear float32, zfar float32, rightHanded bool) Matrix4f {
+	modifier := ProjectionModifier(Projection_None)
+	if rightHanded {
+		modifier = Projection_RightHanded
+	}
+
+	return Projection(fov, znear, zfar, modifier)
+}
+
+// OrthoSubProjection is the pure-Go version of Matrix4f_OrthoSubProjection(),
+// which doesn't need libOVR.
+func OrthoSubProjection(projection Matrix4f, orthoScale Vector2f, orthoDistance float32, eyeViewAdjustX float32) Matrix4f {
+	orthoHorizontalOffset := eyeViewAdjustX / orthoDistance
+
+	m := Matrix4f{}
+	m.M[0][0] = projection.M[0][0] * orthoScale.X
+	m.M[0][3] = -projection.M[0][2] + (orthoHorizontalOffset * projection.M[0][0])
+
+	// Note the sign flip, because text rendering uses Y=down.
+	m.M[1][1] = -projection.M[1][1] * orthoScale.Y
+	m.M[1][3] = -projection.M[1][2]
+
+	// No perspective correction for ortho.
+	m.M[3][3] = 1.0
+
+	return m
+}
@@ -0,0 +1,88 @@
+package ovr
+
+import "testing"
+
+// Returns the depth m maps a point at distance d in front of the camera to.
+func projectedDepth(m Matrix4f, d float32, rightHanded bool) float32 {
+	z := d
+	if rightHanded {
+		z = -d
+	}
+
+	clipZ := m.M[2][2]*z + m.M[2][3]
+	clipW := m.M[3][2] * z
+
+	return clipZ / clipW
+}
+
+func TestProjectionDepth(t *testing.T) {
+	fov := FovPort{UpTan: 1.3292863, DownTan: 1.3292863, LeftTan: 1.0586576, RightTan: 1.092368}
+
+	cases := []struct {
+		modifier        ProjectionModifier
+		description     string
+		expNear, expFar float32
+	}{
+		{Projection_None, "default", 0, 1},
+		{Projection_ClipRangeOpenGL, "OpenGL", -1, 1},
+		{Projection_FarLessThanNear, "reversed-Z", 1, 0},
+		{Projection_FarLessThanNear | Projection_ClipRangeOpenGL, "reversed-Z OpenGL", 1, -1},
+		{Projection_FarClipAtInfinity, "infinite", 0, 1},
+		{Projection_FarClipAtInfinity | Projection_FarLessThanNear, "infinite reversed-Z", 1, 0},
+		{Projection_FarClipAtInfinity | Projection_ClipRangeOpenGL, "infinite OpenGL", -1, 1},
+	}
+
+	for _, c := range cases {
+		for _, rightHanded := range []bool{true, false} {
+			modifier := c.modifier
+			if rightHanded {
+				modifier |= Projection_RightHanded
+			}
+
+			znear, zfar := float32(0.1), float32(1000)
+			m := Projection(fov, znear, zfar, modifier)
+
+			if depth := projectedDepth(m, znear, rightHanded); !approxFloat(c.expNear, depth, 1e-6) {
+				t.Errorf("Expected the %s projection to map the near plane to %f instead of %f", c.description, c.expNear, depth)
+			}
+
+			// With the far plane at infinity, far away points come close to
+			// the far depth.
+			farAway, delta := zfar, float32(1e-6)
+			if c.modifier&Projection_FarClipAtInfinity != 0 {
+				farAway, delta = 1e6, 1e-5
+			}
+
+			if depth := projectedDepth(m, farAway, rightHanded); !approxFloat(c.expFar, depth, delta) {
+				t.Errorf("Expected the %s projection to map a point %f away to %f instead of %f", c.description, farAway, c.expFar, depth)
+			}
+		}
+	}
+}
+
+func TestProjectionMatchesLibOVR(t *testing.T) {
+	hmd := initializeWithHmd()
+	defer destroyAndShutdown(hmd)
+
+	fov := FovPort{UpTan: 1.3292863, DownTan: 1.3292863, LeftTan: 1.0586576, RightTan: 1.092368}
+
+	for _, rightHanded := range []bool{true, false} {
+		modifier := ProjectionModifier(Projection_None)
+		if rightHanded {
+			modifier = Projection_RightHanded
+		}
+
+		exp, result := Matrix4f_Projection(fov, 10, 1000, rightHanded), Projection(fov, 10, 1000, modifier)
+		if !approxMatrix4f(exp, result, 1e-6) {
+			t.Errorf("Expected the projection %v of libOVR instead of %v", exp, result)
+		}
+	}
+
+	proj := Matrix4f_Projection(fov, 10, 1000, true)
+	orthoScale := Vector2f{1.0 / 549.3, 1.0 / 549.3}
+
+	exp, result := Matrix4f_OrthoSubProjection(proj, orthoScale, 0.8, 0.032), OrthoSubProjection(proj, orthoScale, 0.8, 0.032)
+	if !approxMatrix4f(exp, result, 1e-6) {
+		t.Errorf("Expected the orthographic projection %v of libOVR instead of %v", exp, result)
+	}
+}
@@ -0,0 +1,227 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ******************************** [ Euler angles ] **************************
+// ****************************************************************************
+
+// An Axis names one of the axes of the coordinate system, for Euler angles.
+type Axis int
+
+const (
+	Axis_X Axis = 0
+	Axis_Y Axis = 1
+	Axis_Z Axis = 2
+)
+
+// A RotateDirection tells which way a positive angle turns, when looking down
+// the axis towards the origin.
+type RotateDirection int
+
+const (
+	Rotate_CCW RotateDirection = 1
+	Rotate_CW  RotateDirection = -1
+)
+
+// A HandedSystem tells whether the coordinate system is right- or
+// left-handed. libOVR is right-handed, with Y up and -Z forward.
+type HandedSystem int
+
+const (
+	Handed_R HandedSystem = 1
+	Handed_L HandedSystem = -1
+)
+
+// Below this distance from a pole, the first and last Euler angle can't be
+// told apart, and all of the rotation is given to the last one. The SDK uses
+// 1e-7, which is below the rounding error of a Quatf.
+const eulerSingularityRadius = 1e-6
+
+// ****************************************************************************
+// ********************************* [ Quatf ] ********************************
+// ****************************************************************************
+
+// QuatfFromAxisAngle returns the rotation of angle radians around axis. The
+// axis doesn't have to be normalized.
+func QuatfFromAxisAngle(axis Vector3f, angle float32) Quatf {
+	length := float32(math.Sqrt(float64(axis.X*axis.X + axis.Y*axis.Y + axis.Z*axis.Z)))
+	if length == 0 {
+		return Quatf{W: 1}
+	}
+
+	sin, cos := math.Sincos(float64(angle) / 2)
+	s := float32(sin) / length
+
+	return Quatf{axis.X * s, axis.Y * s, axis.Z * s, float32(cos)}
+}
+
+// QuatfFromEulerAngles returns the rotation of angle a around axis a1,
+// followed by angle b around axis a2 and angle c around axis a3, all in the
+// frame of the rotated object. This is the reverse of EulerAngles().
+func QuatfFromEulerAngles(a1, a2, a3 Axis, a, b, c float32, direction RotateDirection, handedness HandedSystem) Quatf {
+	sign := float32(int(direction) * int(handedness))
+
+	axes := [3]Vector3f{{X: 1}, {Y: 1}, {Z: 1}}
+	return QuatfFromAxisAngle(axes[a1], sign*a).
+		Mul(QuatfFromAxisAngle(axes[a2], sign*b)).
+		Mul(QuatfFromAxisAngle(axes[a3], sign*c))
+}
+
+// Mul returns the rotation r followed by q.
+func (q Quatf) Mul(r Quatf) Quatf {
+	return Quatf{
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+	}
+}
+
+// Conjugate returns the conjugate of q, which is its inverse if q is
+// normalized.
+func (q Quatf) Conjugate() Quatf {
+	return Quatf{-q.X, -q.Y, -q.Z, q.W}
+}
+
+// Inverse returns the inverse of q, or q itself if it has a length of zero.
+func (q Quatf) Inverse() Quatf {
+	lengthSq := q.LengthSq()
+	if lengthSq == 0 {
+		return q
+	}
+
+	return Quatf{-q.X / lengthSq, -q.Y / lengthSq, -q.Z / lengthSq, q.W / lengthSq}
+}
+
+func (q Quatf) Dot(r Quatf) float32 {
+	return q.X*r.X + q.Y*r.Y + q.Z*r.Z + q.W*r.W
+}
+
+func (q Quatf) LengthSq() float32 {
+	return q.Dot(q)
+}
+
+func (q Quatf) Length() float32 {
+	return float32(math.Sqrt(float64(q.LengthSq())))
+}
+
+// Normalize returns q scaled to a length of one, or q itself if it has a
+// length of zero.
+func (q Quatf) Normalize() Quatf {
+	length := q.Length()
+	if length == 0 {
+		return q
+	}
+
+	return Quatf{q.X / length, q.Y / length, q.Z / length, q.W / length}
+}
+
+// Rotate returns v rotated by q, which should be normalized.
+func (q Quatf) Rotate(v Vector3f) Vector3f {
+	r := q.Mul(Quatf{v.X, v.Y, v.Z, 0}).Mul(q.Conjugate())
+	return Vector3f{r.X, r.Y, r.Z}
+}
+
+// InverseRotate returns v rotated by the inverse of q, which should be
+// normalized.
+func (q Quatf) InverseRotate(v Vector3f) Vector3f {
+	return q.Conjugate().Rotate(v)
+}
+
+// Slerp interpolates along the shortest arc from q, at f = 0, to r, at f = 1.
+func (q Quatf) Slerp(r Quatf, f float32) Quatf {
+	cosTheta := float64(q.Dot(r))
+
+	// Take the shortest path.
+	if cosTheta < 0 {
+		r = Quatf{-r.X, -r.Y, -r.Z, -r.W}
+		cosTheta = -cosTheta
+	}
+
+	// Close to each other, the quaternions are simply blended, to avoid the
+	// division by a tiny sine.
+	wq, wr := 1-float64(f), float64(f)
+	if cosTheta < 0.9995 {
+		theta := math.Acos(cosTheta)
+		sinTheta := math.Sin(theta)
+		wq = math.Sin((1-float64(f))*theta) / sinTheta
+		wr = math.Sin(float64(f)*theta) / sinTheta
+	}
+
+	return Quatf{
+		X: float32(wq*float64(q.X) + wr*float64(r.X)),
+		Y: float32(wq*float64(q.Y) + wr*float64(r.Y)),
+		Z: float32(wq*float64(q.Z) + wr*float64(r.Z)),
+		W: float32(wq*float64(q.W) + wr*float64(r.W)),
+	}.Normalize()
+}
+
+// AxisAngle returns the axis and the angle in radians q rotates around. The
+// angle is in [0, 2π), and the axis is X for the identity rotation.
+func (q Quatf) AxisAngle() (Vector3f, float32) {
+	q = q.Normalize()
+
+	sinHalf := math.Sqrt(float64(q.X*q.X + q.Y*q.Y + q.Z*q.Z))
+	if sinHalf == 0 {
+		return Vector3f{X: 1}, 0
+	}
+
+	angle := 2 * math.Atan2(sinHalf, float64(q.W))
+	s := float32(1 / sinHalf)
+
+	return Vector3f{q.X * s, q.Y * s, q.Z * s}, float32(angle)
+}
+
+// EulerAngles decomposes q into a rotation of a around axis a1, followed by b
+// around a2 and c around a3, all in the frame of the rotated object. The axes
+// must all differ. Like the SDK, b is in [-π/2, π/2], and at the poles all of
+// the rotation around a1 and a3 is returned in c.
+//
+// To read the yaw, pitch and roll of the head like the SDK samples do, use
+// Axis_Y, Axis_X, Axis_Z with Rotate_CCW and Handed_R, or YawPitchRoll().
+func (q Quatf) EulerAngles(a1, a2, a3 Axis, direction RotateDirection, handedness HandedSystem) (a, b, c float32) {
+	Q := [3]float64{float64(q.X), float64(q.Y), float64(q.Z)}
+	w := float64(q.W)
+	sd := float64(int(direction) * int(handedness))
+
+	ww := w * w
+	Q11, Q22, Q33 := Q[a1]*Q[a1], Q[a2]*Q[a2], Q[a3]*Q[a3]
+
+	// Whether the order of the axes is an even permutation of X, Y, Z.
+	psign := -1.0
+	if (a1+1)%3 == a2 && (a2+1)%3 == a3 {
+		psign = 1.0
+	}
+
+	s2 := psign * 2 * (psign*w*Q[a2] + Q[a1]*Q[a3])
+
+	switch {
+	case s2 < -1+eulerSingularityRadius:
+		// South pole singularity.
+		a = 0
+		b = float32(-sd * math.Pi / 2)
+		c = float32(sd * math.Atan2(2*(psign*Q[a1]*Q[a2]+w*Q[a3]), ww+Q22-Q11-Q33))
+
+	case s2 > 1-eulerSingularityRadius:
+		// North pole singularity.
+		a = 0
+		b = float32(sd * math.Pi / 2)
+		c = float32(sd * math.Atan2(2*(psign*Q[a1]*Q[a2]+w*Q[a3]), ww+Q22-Q11-Q33))
+
+	default:
+		a = float32(-sd * math.Atan2(-2*(w*Q[a1]-psign*Q[a2]*Q[a3]), ww+Q33-Q11-Q22))
+		b = float32(sd * math.Asin(s2))
+		c = float32(sd * math.Atan2(2*(w*Q[a3]-psign*Q[a1]*Q[a2]), ww+Q11-Q22-Q33))
+	}
+
+	return a, b, c
+}
+
+// YawPitchRoll returns the rotation of q around Y, then X, then Z, in
+// libOVR's right-handed coordinate system. A positive yaw turns the head to
+// the left, a positive pitch tilts it up and a positive roll tilts it to the
+// left.
+func (q Quatf) YawPitchRoll() (yaw, pitch, roll float32) {
+	return q.EulerAngles(Axis_Y, Axis_X, Axis_Z, Rotate_CCW, Handed_R)
+}
@@ -0,0 +1,174 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+func approxQuatf(expQuat, calcQuat Quatf, delta float32) bool {
+	// q and -q are the same rotation.
+	if expQuat.Dot(calcQuat) < 0 {
+		calcQuat = Quatf{-calcQuat.X, -calcQuat.Y, -calcQuat.Z, -calcQuat.W}
+	}
+
+	return approxFloat(expQuat.X, calcQuat.X, delta) && approxFloat(expQuat.Y, calcQuat.Y, delta) &&
+		approxFloat(expQuat.Z, calcQuat.Z, delta) && approxFloat(expQuat.W, calcQuat.W, delta)
+}
+
+func approxVector3f(expVec, calcVec Vector3f, delta float32) bool {
+	return approxFloat(expVec.X, calcVec.X, delta) && approxFloat(expVec.Y, calcVec.Y, delta) &&
+		approxFloat(expVec.Z, calcVec.Z, delta)
+}
+
+func TestQuatfAlgebra(t *testing.T) {
+	q := QuatfFromAxisAngle(Vector3f{1, 2, 3}, 0.7)
+	r := QuatfFromAxisAngle(Vector3f{-2, 0.5, 1}, 1.9)
+
+	if length := q.Length(); !approxFloat(1, length, 1e-6) {
+		t.Errorf("Expected a rotation of length 1 instead of %f", length)
+	}
+
+	if result := q.Mul(q.Inverse()); !approxQuatf(Quatf{W: 1}, result, 1e-6) {
+		t.Errorf("Expected q·q⁻¹ to be the identity instead of %v", result)
+	}
+
+	scaled := Quatf{q.X * 2, q.Y * 2, q.Z * 2, q.W * 2}
+	if result := scaled.Mul(scaled.Inverse()); !approxQuatf(Quatf{W: 1}, result, 1e-6) {
+		t.Errorf("Expected the inverse of a scaled quaternion to undo it instead of %v", result)
+	}
+
+	if result := scaled.Normalize(); !approxQuatf(q, result, 1e-6) {
+		t.Errorf("Expected %v instead of %v", q, result)
+	}
+
+	// Rotating by q·r is rotating by r, then by q.
+	v := Vector3f{0.3, -1.2, 2.5}
+	if exp, result := q.Rotate(r.Rotate(v)), q.Mul(r).Rotate(v); !approxVector3f(exp, result, 1e-5) {
+		t.Errorf("Expected %v instead of %v", exp, result)
+	}
+
+	if result := q.InverseRotate(q.Rotate(v)); !approxVector3f(v, result, 1e-5) {
+		t.Errorf("Expected %v instead of %v", v, result)
+	}
+
+	// A quarter turn to the left takes -Z (forward) to -X.
+	yaw := QuatfFromAxisAngle(Vector3f{Y: 1}, math.Pi/2)
+	if result := yaw.Rotate(Vector3f{Z: -1}); !approxVector3f(Vector3f{X: -1}, result, 1e-6) {
+		t.Errorf("Expected a quarter turn around Y to take -Z to -X instead of %v", result)
+	}
+}
+
+func TestQuatfAxisAngle(t *testing.T) {
+	axis := Vector3f{0.48, -0.6, 0.64}
+	for _, angle := range []float32{0.1, 1, 3, 4, 6} {
+		calcAxis, calcAngle := QuatfFromAxisAngle(axis, angle).AxisAngle()
+		if !approxVector3f(axis, calcAxis, 1e-5) || !approxFloat(angle, calcAngle, 1e-5) {
+			t.Errorf("Expected axis %v and angle %f instead of %v and %f", axis, angle, calcAxis, calcAngle)
+		}
+	}
+
+	if calcAxis, calcAngle := (Quatf{W: 1}).AxisAngle(); calcAxis != (Vector3f{X: 1}) || calcAngle != 0 {
+		t.Errorf("Expected the identity to have an angle of 0 around X instead of %f around %v", calcAngle, calcAxis)
+	}
+}
+
+func TestQuatfSlerp(t *testing.T) {
+	axis := Vector3f{0, 1, 0}
+	q := QuatfFromAxisAngle(axis, 0.2)
+	r := QuatfFromAxisAngle(axis, 1.4)
+
+	cases := []struct {
+		f   float32
+		exp Quatf
+	}{
+		{0, q},
+		{0.5, QuatfFromAxisAngle(axis, 0.8)},
+		{1, r},
+	}
+
+	for _, c := range cases {
+		if result := q.Slerp(r, c.f); !approxQuatf(c.exp, result, 1e-6) {
+			t.Errorf("Expected %v at %f instead of %v", c.exp, c.f, result)
+		}
+	}
+
+	// -r is the same rotation as r, and the interpolation shouldn't go the long
+	// way around.
+	negated := Quatf{-r.X, -r.Y, -r.Z, -r.W}
+	if result := q.Slerp(negated, 0.5); !approxQuatf(QuatfFromAxisAngle(axis, 0.8), result, 1e-6) {
+		t.Errorf("Expected Slerp() to take the shortest path instead of %v", result)
+	}
+
+	// Close rotations are blended.
+	s := QuatfFromAxisAngle(axis, 0.2001)
+	if result := q.Slerp(s, 0.5); !approxQuatf(QuatfFromAxisAngle(axis, 0.20005), result, 1e-6) {
+		t.Errorf("Expected %v instead of %v", QuatfFromAxisAngle(axis, 0.20005), result)
+	}
+}
+
+func TestQuatfEulerAngles(t *testing.T) {
+	orders := [][3]Axis{
+		{Axis_Y, Axis_X, Axis_Z},
+		{Axis_X, Axis_Y, Axis_Z},
+		{Axis_Z, Axis_Y, Axis_X},
+		{Axis_X, Axis_Z, Axis_Y},
+	}
+
+	angles := [][3]float32{
+		{0.3, -0.4, 1.2},
+		{-2.5, 1.1, -0.1},
+		{3, 0.05, 2},
+	}
+
+	for _, order := range orders {
+		for _, handedness := range []HandedSystem{Handed_R, Handed_L} {
+			for _, direction := range []RotateDirection{Rotate_CCW, Rotate_CW} {
+				for _, exp := range angles {
+					q := QuatfFromEulerAngles(order[0], order[1], order[2], exp[0], exp[1], exp[2], direction, handedness)
+					a, b, c := q.EulerAngles(order[0], order[1], order[2], direction, handedness)
+
+					if !approxFloat(exp[0], a, 1e-5) || !approxFloat(exp[1], b, 1e-5) || !approxFloat(exp[2], c, 1e-5) {
+						t.Errorf("Expected angles %v around %v (%d, %d) instead of [%f %f %f]", exp, order, direction, handedness, a, b, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestQuatfEulerAnglesAtThePoles(t *testing.T) {
+	for _, pitch := range []float32{math.Pi / 2, -math.Pi / 2} {
+		q := QuatfFromEulerAngles(Axis_Y, Axis_X, Axis_Z, 0.4, pitch, 0.7, Rotate_CCW, Handed_R)
+		yaw, calcPitch, roll := q.YawPitchRoll()
+
+		if yaw != 0 || !approxFloat(pitch, calcPitch, 1e-6) {
+			t.Errorf("Expected a yaw of 0 and a pitch of %f instead of %f and %f", pitch, yaw, calcPitch)
+		}
+
+		// Only the combined rotation around the vertical axis is known.
+		if result := QuatfFromEulerAngles(Axis_Y, Axis_X, Axis_Z, yaw, calcPitch, roll, Rotate_CCW, Handed_R); !approxQuatf(q, result, 1e-5) {
+			t.Errorf("Expected the angles %f, %f, %f to give back %v instead of %v", yaw, calcPitch, roll, q, result)
+		}
+	}
+}
+
+func TestQuatfYawPitchRoll(t *testing.T) {
+	angles := [][3]float64{
+		{0.5, 0, 0},
+		{0, -0.3, 0},
+		{0, 0, 0.8},
+		{-1.2, 0.4, -0.25},
+		{2.8, -1.1, 1.5},
+	}
+
+	// The motion scripts build orientations from yaw, pitch and roll like the
+	// SDK does.
+	for _, exp := range angles {
+		sample := motionSample{angles: exp}
+		yaw, pitch, roll := sample.poseState(0).ThePose.Orientation.YawPitchRoll()
+
+		if !approxFloat(float32(exp[0]), yaw, 1e-5) || !approxFloat(float32(exp[1]), pitch, 1e-5) || !approxFloat(float32(exp[2]), roll, 1e-5) {
+			t.Errorf("Expected yaw, pitch and roll %v instead of %f, %f and %f", exp, yaw, pitch, roll)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package ovr
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ****************************************************************************
+// ************************** [ Recording format ] ****************************
+// ****************************************************************************
+
+// A recording starts with the magic bytes and the version of the format, both
+// followed by the header and any number of tracking records. All values are
+// stored in little-endian byte order, and strings are prefixed with their
+// length as a uint16.
+const (
+	recordingMagic   = "OVRTRACK"
+	recordingVersion = 1
+)
+
+var recordingByteOrder = binary.LittleEndian
+
+// The fixed-size part of the header. The strings follow it in the order
+// VersionString, ProductName, Manufacturer, SerialNumber, DisplayDeviceName.
+type recordingHeader struct {
+	StartTime                  int64
+	Type                       int32
+	VendorId                   int32
+	ProductId                  int32
+	FirmwareMajor              int32
+	FirmwareMinor              int32
+	CameraFrustumHFovInRadians float32
+	CameraFrustumVFovInRadians float32
+	CameraFrustumNearZInMeters float32
+	CameraFrustumFarZInMeters  float32
+	HmdCaps                    uint32
+	TrackingCaps               uint32
+	DistortionCaps             uint32
+	DefaultEyeFov              [Eye_Count]FovPort
+	MaxEyeFov                  [Eye_Count]FovPort
+	EyeRenderOrder             [Eye_Count]int32
+	Resolution                 [2]int32
+	WindowsPos                 [2]int32
+	DisplayId                  int32
+}
+
+// A single TrackingState.
+type trackingRecord struct {
+	HeadPose          Posef
+	AngularVelocity   Vector3f
+	LinearVelocity    Vector3f
+	AngularAccel      Vector3f
+	LinearAccel       Vector3f
+	TimeInSeconds     float64
+	CameraPose        Posef
+	LeveledCameraPose Posef
+	RawSensorData     SensorData
+	StatusFlags       uint32
+}
+
+func newTrackingRecord(state TrackingState) trackingRecord {
+	return trackingRecord{
+		HeadPose:          state.HeadPose.ThePose,
+		AngularVelocity:   state.HeadPose.AngularVelocity,
+		LinearVelocity:    state.HeadPose.LinearVelocity,
+		AngularAccel:      state.HeadPose.AngularAcceleration,
+		LinearAccel:       state.HeadPose.LinearAcceleration,
+		TimeInSeconds:     state.HeadPose.TimeInSeconds,
+		CameraPose:        state.CameraPose,
+		LeveledCameraPose: state.LeveledCameraPose,
+		RawSensorData:     state.RawSensorData,
+		StatusFlags:       uint32(state.StatusFlags),
+	}
+}
+
+func (record trackingRecord) trackingState() TrackingState {
+	return TrackingState{
+		HeadPose: PoseStatef{
+			ThePose:             record.HeadPose,
+			AngularVelocity:     record.AngularVelocity,
+			LinearVelocity:      record.LinearVelocity,
+			AngularAcceleration: record.AngularAccel,
+			LinearAcceleration:  record.LinearAccel,
+			TimeInSeconds:       record.TimeInSeconds,
+		},
+		CameraPose:        record.CameraPose,
+		LeveledCameraPose: record.LeveledCameraPose,
+		RawSensorData:     record.RawSensorData,
+		StatusFlags:       uint(record.StatusFlags),
+	}
+}
+
+// The header of a recording.
+type RecordingHeader struct {
+	// The SDK version string of the device that was recorded.
+	VersionString string
+
+	// When the recording was started.
+	StartTime time.Time
+
+	// The descriptor of the recorded device. It only holds the description,
+	// so none of its methods can be called.
+	Hmd Hmd
+}
+
+func writeString(w io.Writer, str string) error {
+	if len(str) > 0xFFFF {
+		return fmt.Errorf("string of %d bytes is too long for a recording", len(str))
+	}
+
+	if err := binary.Write(w, recordingByteOrder, uint16(len(str))); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, str)
+	return err
+}
+
+func readString(r io.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, recordingByteOrder, &length); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+func writeRecordingHeader(w io.Writer, header RecordingHeader) error {
+	hmd := header.Hmd
+	fixed := recordingHeader{
+		StartTime:                  header.StartTime.UnixNano(),
+		Type:                       int32(hmd.Type),
+		VendorId:                   int32(hmd.VendorId),
+		ProductId:                  int32(hmd.ProductId),
+		FirmwareMajor:              int32(hmd.FirmwareMajor),
+		FirmwareMinor:              int32(hmd.FirmwareMinor),
+		CameraFrustumHFovInRadians: hmd.CameraFrustumHFovInRadians,
+		CameraFrustumVFovInRadians: hmd.CameraFrustumVFovInRadians,
+		CameraFrustumNearZInMeters: hmd.CameraFrustumNearZInMeters,
+		CameraFrustumFarZInMeters:  hmd.CameraFrustumFarZInMeters,
+		HmdCaps:                    uint32(hmd.HmdCaps),
+		TrackingCaps:               uint32(hmd.TrackingCaps),
+		DistortionCaps:             uint32(hmd.DistortionCaps),
+		DefaultEyeFov:              hmd.DefaultEyeFov,
+		MaxEyeFov:                  hmd.MaxEyeFov,
+		EyeRenderOrder:             [Eye_Count]int32{int32(hmd.EyeRenderOrder[0]), int32(hmd.EyeRenderOrder[1])},
+		Resolution:                 [2]int32{int32(hmd.Resolution.W), int32(hmd.Resolution.H)},
+		WindowsPos:                 [2]int32{int32(hmd.WindowsPos.X), int32(hmd.WindowsPos.Y)},
+		DisplayId:                  int32(hmd.DisplayId),
+	}
+
+	if _, err := io.WriteString(w, recordingMagic); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, recordingByteOrder, uint16(recordingVersion)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, recordingByteOrder, &fixed); err != nil {
+		return err
+	}
+
+	for _, str := range []string{header.VersionString, hmd.ProductName, hmd.Manufacturer, hmd.SerialNumber, hmd.DisplayDeviceName} {
+		if err := writeString(w, str); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readRecordingHeader(r io.Reader) (RecordingHeader, error) {
+	magic := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return RecordingHeader{}, err
+	}
+
+	if string(magic) != recordingMagic {
+		return RecordingHeader{}, errors.New("not a tracking recording")
+	}
+
+	var version uint16
+	if err := binary.Read(r, recordingByteOrder, &version); err != nil {
+		return RecordingHeader{}, err
+	}
+
+	if version != recordingVersion {
+		return RecordingHeader{}, fmt.Errorf("unsupported recording version %d", version)
+	}
+
+	fixed := recordingHeader{}
+	if err := binary.Read(r, recordingByteOrder, &fixed); err != nil {
+		return RecordingHeader{}, err
+	}
+
+	strs := [5]string{}
+	for i := range strs {
+		str, err := readString(r)
+		if err != nil {
+			return RecordingHeader{}, err
+		}
+
+		strs[i] = str
+	}
+
+	return RecordingHeader{
+		VersionString: strs[0],
+		StartTime:     time.Unix(0, fixed.StartTime),
+		Hmd: Hmd{
+			Type:                       HmdType(fixed.Type),
+			ProductName:                strs[1],
+			Manufacturer:               strs[2],
+			VendorId:                   int(fixed.VendorId),
+			ProductId:                  int(fixed.ProductId),
+			SerialNumber:               strs[3],
+			FirmwareMajor:              int(fixed.FirmwareMajor),
+			FirmwareMinor:              int(fixed.FirmwareMinor),
+			CameraFrustumHFovInRadians: fixed.CameraFrustumHFovInRadians,
+			CameraFrustumVFovInRadians: fixed.CameraFrustumVFovInRadians,
+			CameraFrustumNearZInMeters: fixed.CameraFrustumNearZInMeters,
+			CameraFrustumFarZInMeters:  fixed.CameraFrustumFarZInMeters,
+			HmdCaps:                    uint(fixed.HmdCaps),
+			TrackingCaps:               uint(fixed.TrackingCaps),
+			DistortionCaps:             uint(fixed.DistortionCaps),
+			DefaultEyeFov:              fixed.DefaultEyeFov,
+			MaxEyeFov:                  fixed.MaxEyeFov,
+			EyeRenderOrder:             [Eye_Count]EyeType{EyeType(fixed.EyeRenderOrder[0]), EyeType(fixed.EyeRenderOrder[1])},
+			Resolution:                 Sizei{int(fixed.Resolution[0]), int(fixed.Resolution[1])},
+			WindowsPos:                 Vector2i{int(fixed.WindowsPos[0]), int(fixed.WindowsPos[1])},
+			DisplayDeviceName:          strs[4],
+			DisplayId:                  int(fixed.DisplayId),
+		},
+	}, nil
+}
+
+// ****************************************************************************
+// ****************************** [ Recorder ] ********************************
+// ****************************************************************************
+
+// A Recorder logs every TrackingState an Hmd returns from GetTrackingState().
+type Recorder struct {
+	mutex  sync.Mutex
+	hmd    *Hmd
+	layer  *recordingDevice
+	w      *bufio.Writer
+	closer io.Closer
+	err    error
+}
+
+// A recordingDevice passes every call on to the device it wraps, and hands
+// the tracking states to the recorder on the way back.
+type recordingDevice struct {
+	device
+	rec *Recorder
+}
+
+func (dev *recordingDevice) unwrap() device {
+	return dev.device
+}
+
+func (dev *recordingDevice) rewrap(inner device) {
+	dev.device = inner
+}
+
+func (dev *recordingDevice) trackingState(absTime float64) TrackingState {
+	state := dev.device.trackingState(absTime)
+	dev.rec.Record(state)
+
+	return state
+}
+
+// NewRecorder starts recording the tracking states of hmd to w, until Close()
+// is called. Only one recorder can be active on an Hmd at a time, and it must
+// not be started or stopped while other goroutines use the Hmd.
+func NewRecorder(hmd *Hmd, w io.Writer) (*Recorder, error) {
+	for dev := hmd.dev; dev != nil; {
+		if _, ok := dev.(*recordingDevice); ok {
+			return nil, errors.New("the device is already being recorded")
+		}
+
+		wrapper, ok := dev.(deviceWrapper)
+		if !ok {
+			break
+		}
+		dev = wrapper.unwrap()
+	}
+
+	rec := &Recorder{hmd: hmd, w: bufio.NewWriter(w)}
+
+	// Only the descriptor is recorded, not the device behind it.
+	desc := *hmd
+	desc.dev = nil
+
+	header := RecordingHeader{
+		VersionString: GetVersionString(),
+		StartTime:     time.Now(),
+		Hmd:           desc,
+	}
+
+	if err := writeRecordingHeader(rec.w, header); err != nil {
+		return nil, err
+	}
+
+	rec.layer = &recordingDevice{device: hmd.dev, rec: rec}
+	hmd.dev = rec.layer
+
+	return rec, nil
+}
+
+// CreateRecording starts recording the tracking states of hmd to a file.
+func CreateRecording(hmd *Hmd, filename string) (*Recorder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := NewRecorder(hmd, file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	rec.closer = file
+	return rec, nil
+}
+
+// Record adds a tracking state to the recording. This is done automatically
+// for every call to GetTrackingState() on the recorded Hmd, but can be used
+// to add states that were obtained some other way. Once writing fails, all
+// subsequent calls return the same error.
+func (rec *Recorder) Record(state TrackingState) error {
+	rec.mutex.Lock()
+	defer rec.mutex.Unlock()
+
+	if rec.err == nil {
+		record := newTrackingRecord(state)
+		rec.err = binary.Write(rec.w, recordingByteOrder, &record)
+	}
+
+	return rec.err
+}
+
+// Close stops recording and flushes the recording. If the recording was
+// started with CreateRecording(), the file is closed as well.
+func (rec *Recorder) Close() error {
+	rec.mutex.Lock()
+	defer rec.mutex.Unlock()
+
+	// Wrappers added after the recorder started, like a session's, stay in
+	// place.
+	if rec.hmd != nil {
+		removeWrapper(rec.hmd, rec.layer)
+		rec.hmd = nil
+	}
+
+	if rec.err == nil {
+		rec.err = rec.w.Flush()
+	}
+
+	err := rec.err
+	if rec.closer != nil {
+		if closeErr := rec.closer.Close(); err == nil {
+			err = closeErr
+		}
+		rec.closer = nil
+	}
+
+	if rec.err == nil {
+		rec.err = errors.New("the recorder is closed")
+	}
+
+	return err
+}
+
+// ****************************************************************************
+// *************************** [ Recording reader ] ***************************
+// ****************************************************************************
+
+// A RecordingReader reads back the tracking states of a recording.
+type RecordingReader struct {
+	r      io.Reader
+	header RecordingHeader
+}
+
+// NewRecordingReader reads the header of a recording from r, and returns a
+// reader for the tracking states that follow it.
+func NewRecordingReader(r io.Reader) (*RecordingReader, error) {
+	br := bufio.NewReader(r)
+
+	header, err := readRecordingHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordingReader{r: br, header: header}, nil
+}
+
+// Header returns the header of the recording.
+func (reader *RecordingReader) Header() RecordingHeader {
+	return reader.header
+}
+
+// Next returns the next tracking state of the recording, or io.EOF when there
+// are no more.
+func (reader *RecordingReader) Next() (TrackingState, error) {
+	record := trackingRecord{}
+	if err := binary.Read(reader.r, recordingByteOrder, &record); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return TrackingState{}, errors.New("the recording is truncated")
+		}
+
+		return TrackingState{}, err
+	}
+
+	return record.trackingState(), nil
+}
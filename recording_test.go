@@ -0,0 +1,134 @@
+package ovr
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+	hmd.Simulator().SetMotionScript(testMotionScript(), 0)
+
+	buf := &bytes.Buffer{}
+	rec, err := NewRecorder(hmd, buf)
+	if err != nil {
+		t.Fatalf("Expected NewRecorder() to succeed, instead of '%s'", err)
+	}
+
+	if hmd.Simulator() == nil {
+		t.Error("Expected Simulator() to see through the recorder")
+	}
+
+	states := []TrackingState{}
+	for i := 0; i < 100; i++ {
+		states = append(states, hmd.GetTrackingState(float64(i)*0.05))
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Expected Close() to succeed, instead of '%s'", err)
+	}
+
+	// Tracking states after Close() aren't recorded.
+	hmd.GetTrackingState(10)
+
+	reader, err := NewRecordingReader(buf)
+	if err != nil {
+		t.Fatalf("Expected NewRecordingReader() to succeed, instead of '%s'", err)
+	}
+
+	header := reader.Header()
+	if header.VersionString != GetVersionString() {
+		t.Errorf("Expected version string '%s', instead of '%s'", GetVersionString(), header.VersionString)
+	}
+
+	if header.Hmd.ProductName != hmd.ProductName || header.Hmd.DefaultEyeFov != hmd.DefaultEyeFov || header.Hmd.Resolution != hmd.Resolution {
+		t.Error("Expected the header to hold the descriptor of the recorded device")
+	}
+
+	for i, expState := range states {
+		calcState, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Expected tracking state %d, instead of '%s'", i, err)
+		}
+
+		if calcState != expState {
+			t.Errorf("Expected tracking state %d to be %v, instead of %v", i, expState, calcState)
+		}
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("Expected the recording to end after %d tracking states", len(states))
+	}
+}
+
+func TestRecordingReaderRejectsGarbage(t *testing.T) {
+	if _, err := NewRecordingReader(bytes.NewBufferString("This is not a recording")); err == nil {
+		t.Error("Expected NewRecordingReader() to reject garbage")
+	}
+}
+
+// A countingDevice counts the tracking states asked for through it.
+type countingDevice struct {
+	device
+	calls int
+}
+
+func (dev *countingDevice) unwrap() device {
+	return dev.device
+}
+
+func (dev *countingDevice) rewrap(inner device) {
+	dev.device = inner
+}
+
+func (dev *countingDevice) trackingState(absTime float64) TrackingState {
+	dev.calls++
+	return dev.device.trackingState(absTime)
+}
+
+func TestRecorderCloseKeepsOuterWrappers(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	buf := &bytes.Buffer{}
+	rec, err := NewRecorder(hmd, buf)
+	if err != nil {
+		t.Fatalf("Expected NewRecorder() to succeed, instead of '%s'", err)
+	}
+
+	counter := &countingDevice{device: hmd.dev}
+	hmd.dev = counter
+
+	if _, err := NewRecorder(hmd, &bytes.Buffer{}); err == nil {
+		t.Error("Expected a second recorder below another wrapper to be refused")
+	}
+
+	hmd.GetTrackingState(0)
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Expected Close() to succeed, instead of '%s'", err)
+	}
+
+	hmd.GetTrackingState(1)
+
+	if hmd.dev != counter || counter.device != hmd.Simulator() || counter.calls != 2 {
+		t.Errorf("Expected Close() to only remove the recorder, instead of leaving %#v", hmd.dev)
+	}
+
+	reader, err := NewRecordingReader(buf)
+	if err != nil {
+		t.Fatalf("Expected NewRecordingReader() to succeed, instead of '%s'", err)
+	}
+
+	if _, err := reader.Next(); err != nil {
+		t.Errorf("Expected the state before Close() to be recorded, instead of '%s'", err)
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("Expected the state after Close() not to be recorded, instead of '%v'", err)
+	}
+}
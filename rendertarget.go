@@ -0,0 +1,143 @@
+package ovr
+
+// ****************************************************************************
+// *************************** [ Render targets ] *****************************
+// ****************************************************************************
+
+// How the eyes are laid out on the render targets.
+const (
+	// Both eyes share a texture, the left eye on the left half.
+	Layout_SideBySide = 0
+
+	// Both eyes share a texture, the left eye on the top half.
+	Layout_TopBottom = 1
+
+	// Each eye has its own texture.
+	Layout_Separate = 2
+)
+
+type RenderTargetLayout int
+
+// RenderTargetOptions are the constraints on the render targets
+// PlanRenderTargets() lays out.
+type RenderTargetOptions struct {
+	// The API filled in the texture headers.
+	API RenderAPIType
+
+	// The largest width and height a texture can have, like
+	// GL_MAX_TEXTURE_SIZE. Zero means there is no limit.
+	MaxTextureSize int
+
+	// The width and height of the viewports are rounded up to a multiple of
+	// Alignment, so the viewports start at aligned offsets. Zero means 1. An
+	// Alignment larger than MaxTextureSize is lowered to it, so an eye is never
+	// smaller than one alignment unit.
+	Alignment int
+}
+
+// A RenderTargetPlan says which textures to create, and where each eye is
+// rendered on them.
+type RenderTargetPlan struct {
+	Layout RenderTargetLayout
+
+	// The pixel density the eyes are rendered at. It is lower than the one
+	// requested when an eye doesn't fit in the largest texture.
+	PixelsPerDisplayPixel float32
+
+	// The size of the texture of each eye, indexed by eye. Unless the layout
+	// is Layout_Separate, both eyes share the same texture.
+	TextureSize [Eye_Count]Sizei
+
+	// The viewport of each eye on its texture, indexed by eye.
+	Viewport [Eye_Count]Recti
+
+	// The headers of the eye textures to pass to EndFrame(), indexed by eye.
+	// Only the platform data of the textures is left to fill in.
+	Header [Eye_Count]TextureHeader
+}
+
+// PlanRenderTargets lays out the render targets of both eyes, with the size
+// GetFovTextureSize() recommends for eyeFov at pixelsPerDisplayPixel. Both eyes
+// share a texture side by side if it fits in options.MaxTextureSize, or else
+// top to bottom. If neither fits, each eye gets its own texture, and if an eye
+// doesn't fit on its own, the pixel density is lowered until it does.
+func (hmd *Hmd) PlanRenderTargets(eyeFov [Eye_Count]FovPort, pixelsPerDisplayPixel float32, options RenderTargetOptions) RenderTargetPlan {
+	plan := RenderTargetPlan{PixelsPerDisplayPixel: pixelsPerDisplayPixel}
+
+	alignment := max(options.Alignment, 1)
+	if options.MaxTextureSize > 0 {
+		alignment = min(alignment, options.MaxTextureSize)
+	}
+	sizes := hmd.eyeTextureSizes(eyeFov, pixelsPerDisplayPixel, alignment)
+
+	if limit := options.MaxTextureSize; limit > 0 {
+		largest := 0
+		for _, size := range sizes {
+			largest = max(largest, max(size.W, size.H))
+		}
+
+		if largest > limit {
+			plan.PixelsPerDisplayPixel *= float32(limit) / float32(largest)
+			sizes = hmd.eyeTextureSizes(eyeFov, plan.PixelsPerDisplayPixel, alignment)
+
+			// Rounding may still make an eye a bit too large.
+			aligned := limit / alignment * alignment
+			for eye := range sizes {
+				sizes[eye] = Sizei{min(sizes[eye].W, aligned), min(sizes[eye].H, aligned)}
+			}
+		}
+	}
+
+	left, right := sizes[Eye_Left], sizes[Eye_Right]
+	sideBySide := Sizei{left.W + right.W, max(left.H, right.H)}
+	topBottom := Sizei{max(left.W, right.W), left.H + right.H}
+
+	switch {
+	case fitsTexture(sideBySide, options.MaxTextureSize):
+		plan.Layout = Layout_SideBySide
+		plan.TextureSize = [Eye_Count]Sizei{sideBySide, sideBySide}
+		plan.Viewport[Eye_Left] = Recti{Size: left}
+		plan.Viewport[Eye_Right] = Recti{Pos: Vector2i{X: left.W}, Size: right}
+
+	case fitsTexture(topBottom, options.MaxTextureSize):
+		plan.Layout = Layout_TopBottom
+		plan.TextureSize = [Eye_Count]Sizei{topBottom, topBottom}
+		plan.Viewport[Eye_Left] = Recti{Size: left}
+		plan.Viewport[Eye_Right] = Recti{Pos: Vector2i{Y: left.H}, Size: right}
+
+	default:
+		plan.Layout = Layout_Separate
+		plan.TextureSize = sizes
+		plan.Viewport[Eye_Left] = Recti{Size: left}
+		plan.Viewport[Eye_Right] = Recti{Size: right}
+	}
+
+	for eye := range plan.Header {
+		plan.Header[eye] = TextureHeader{
+			API:            options.API,
+			TextureSize:    plan.TextureSize[eye],
+			RenderViewport: plan.Viewport[eye],
+		}
+	}
+
+	return plan
+}
+
+// Returns the texture size of each eye, rounded up to the alignment.
+func (hmd *Hmd) eyeTextureSizes(eyeFov [Eye_Count]FovPort, pixelsPerDisplayPixel float32, alignment int) [Eye_Count]Sizei {
+	sizes := [Eye_Count]Sizei{}
+	for eye := range sizes {
+		size := hmd.GetFovTextureSize(EyeType(eye), eyeFov[eye], pixelsPerDisplayPixel)
+		sizes[eye] = Sizei{alignUp(size.W, alignment), alignUp(size.H, alignment)}
+	}
+
+	return sizes
+}
+
+func alignUp(n, alignment int) int {
+	return (n + alignment - 1) / alignment * alignment
+}
+
+func fitsTexture(size Sizei, maxTextureSize int) bool {
+	return maxTextureSize <= 0 || (size.W <= maxTextureSize && size.H <= maxTextureSize)
+}
@@ -0,0 +1,108 @@
+package ovr
+
+import "testing"
+
+func TestPlanRenderTargets(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	eyeSize := hmd.GetFovTextureSize(Eye_Left, hmd.DefaultEyeFov[Eye_Left], 1)
+
+	cases := []struct {
+		maxTextureSize int
+		expLayout      RenderTargetLayout
+		expTexture     Sizei
+		expRightPos    Vector2i
+	}{
+		{0, Layout_SideBySide, Sizei{eyeSize.W * 2, eyeSize.H}, Vector2i{X: eyeSize.W}},
+		{4096, Layout_SideBySide, Sizei{eyeSize.W * 2, eyeSize.H}, Vector2i{X: eyeSize.W}},
+		{2048, Layout_Separate, eyeSize, Vector2i{}},
+	}
+
+	for _, c := range cases {
+		plan := hmd.PlanRenderTargets(hmd.DefaultEyeFov, 1, RenderTargetOptions{API: RenderAPI_OpenGL, MaxTextureSize: c.maxTextureSize})
+
+		if plan.Layout != c.expLayout || plan.PixelsPerDisplayPixel != 1 {
+			t.Errorf("Expected layout %d at full density under %d, instead of %d at %f", c.expLayout, c.maxTextureSize, plan.Layout, plan.PixelsPerDisplayPixel)
+		}
+
+		expViewport := [Eye_Count]Recti{{Size: eyeSize}, {Pos: c.expRightPos, Size: eyeSize}}
+		if plan.TextureSize[Eye_Left] != c.expTexture || plan.TextureSize[Eye_Right] != c.expTexture || plan.Viewport != expViewport {
+			t.Errorf("Expected textures of %v with viewports %v, instead of %v with %v", c.expTexture, expViewport, plan.TextureSize, plan.Viewport)
+		}
+
+		for eye, header := range plan.Header {
+			expHeader := TextureHeader{API: RenderAPI_OpenGL, TextureSize: c.expTexture, RenderViewport: expViewport[eye]}
+			if header != expHeader {
+				t.Errorf("Expected the header %v for eye %d, instead of %v", expHeader, eye, header)
+			}
+		}
+	}
+}
+
+func TestPlanRenderTargetsTopBottom(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	// Wide eyes are stacked when they don't fit next to each other.
+	eyeFov := [Eye_Count]FovPort{FovPortFromDegrees(120, 60), FovPortFromDegrees(120, 60)}
+	eyeSize := hmd.GetFovTextureSize(Eye_Left, eyeFov[Eye_Left], 1)
+
+	plan := hmd.PlanRenderTargets(eyeFov, 1, RenderTargetOptions{MaxTextureSize: eyeSize.W + 1})
+
+	if plan.Layout != Layout_TopBottom || plan.TextureSize[Eye_Left] != (Sizei{eyeSize.W, eyeSize.H * 2}) {
+		t.Errorf("Expected a texture of %dx%d with the eyes on top of each other, instead of layout %d with %v", eyeSize.W, eyeSize.H*2, plan.Layout, plan.TextureSize)
+	}
+
+	if exp := (Recti{Pos: Vector2i{Y: eyeSize.H}, Size: eyeSize}); plan.Viewport[Eye_Right] != exp {
+		t.Errorf("Expected the right eye in the bottom half %v, instead of %v", exp, plan.Viewport[Eye_Right])
+	}
+}
+
+func TestPlanRenderTargetsAlignment(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	plan := hmd.PlanRenderTargets(hmd.DefaultEyeFov, 1, RenderTargetOptions{Alignment: 16})
+
+	for eye, viewport := range plan.Viewport {
+		if viewport.Pos.X%16 != 0 || viewport.Size.W%16 != 0 || viewport.Size.H%16 != 0 {
+			t.Errorf("Expected the viewport of eye %d to be aligned to 16 pixels, instead of %v", eye, viewport)
+		}
+
+		size := hmd.GetFovTextureSize(EyeType(eye), hmd.DefaultEyeFov[eye], 1)
+		if viewport.Size.W < size.W || viewport.Size.W >= size.W+16 || viewport.Size.H < size.H || viewport.Size.H >= size.H+16 {
+			t.Errorf("Expected the viewport of eye %d to be rounded up from %v, instead of %v", eye, size, viewport.Size)
+		}
+	}
+}
+
+func TestPlanRenderTargetsLowersDensity(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	plan := hmd.PlanRenderTargets(hmd.DefaultEyeFov, 1.5, RenderTargetOptions{MaxTextureSize: 1024, Alignment: 4})
+
+	if plan.Layout != Layout_Separate || plan.PixelsPerDisplayPixel >= 1 {
+		t.Errorf("Expected separate textures at a lower density, instead of layout %d at %f", plan.Layout, plan.PixelsPerDisplayPixel)
+	}
+
+	for eye, size := range plan.TextureSize {
+		if size.W > 1024 || size.H > 1024 || size.H < 1000 || size.W%4 != 0 || size.H%4 != 0 {
+			t.Errorf("Expected the texture of eye %d to fit in 1024 pixels and stay aligned, instead of %v", eye, size)
+		}
+	}
+}
+
+func TestPlanRenderTargetsAlignmentAboveLimit(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	plan := hmd.PlanRenderTargets(hmd.DefaultEyeFov, 1, RenderTargetOptions{MaxTextureSize: 8, Alignment: 16})
+
+	for eye, size := range plan.TextureSize {
+		if size != (Sizei{8, 8}) {
+			t.Errorf("Expected the texture of eye %d to be lowered to 8x8, instead of %v", eye, size)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package ovr
+
+import (
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+)
+
+// A Player plays back a recording as if it were a live device. It maps the
+// time on the clock of GetTimeInSeconds() to a position in the recording,
+// which advances at the playback speed.
+type Player struct {
+	mutex  sync.Mutex
+	header RecordingHeader
+	states []TrackingState
+	speed  float64
+	loop   bool
+
+	// At anchorTime, the playback was at anchorPosition seconds into the
+	// recording.
+	anchorTime     float64
+	anchorPosition float64
+}
+
+// HmdCreateReplay reads a recording made by a Recorder from r, and returns an
+// Hmd that plays it back. The descriptor of the Hmd is the one of the recorded
+// device. Apart from the tracking, it behaves like a simulated device, so it
+// works with every backend. Playback starts right away.
+func HmdCreateReplay(r io.Reader) (*Hmd, error) {
+	reader, err := NewRecordingReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	player := &Player{header: reader.Header(), speed: 1}
+	for {
+		state, err := reader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		player.states = append(player.states, state)
+	}
+
+	if len(player.states) == 0 {
+		return nil, errors.New("the recording holds no tracking states")
+	}
+
+	// GetTrackingState() can be asked for states in the future, so the
+	// recorded states aren't necessarily in order.
+	sort.SliceStable(player.states, func(i, j int) bool {
+		return player.states[i].HeadPose.TimeInSeconds < player.states[j].HeadPose.TimeInSeconds
+	})
+
+	player.anchorTime = GetTimeInSeconds()
+
+	// Devices that aren't simulated are played back with the display and
+	// timing of a DK2.
+	profile, ok := newSimProfile(player.header.Hmd.Type)
+	if !ok {
+		profile, _ = newSimProfile(Hmd_DK2)
+	}
+	profile.desc = player.header.Hmd
+
+	sim := newSimulator(profile)
+	sim.player = player
+
+	return sim.hmd(), nil
+}
+
+// HmdOpenRecording opens a recording file and returns an Hmd that plays it
+// back. See HmdCreateReplay().
+func HmdOpenRecording(filename string) (*Hmd, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return HmdCreateReplay(file)
+}
+
+// Player returns the player behind hmd, or nil if hmd doesn't play back a
+// recording.
+func (hmd *Hmd) Player() *Player {
+	if sim := hmd.Simulator(); sim != nil {
+		return sim.player
+	}
+
+	return nil
+}
+
+// Header returns the header of the recording.
+func (player *Player) Header() RecordingHeader {
+	return player.header
+}
+
+// Duration returns the length of the recording in seconds.
+func (player *Player) Duration() float64 {
+	return player.states[len(player.states)-1].HeadPose.TimeInSeconds - player.states[0].HeadPose.TimeInSeconds
+}
+
+// Returns the position in the recording at absTime, and whether playback is
+// moving at that time.
+func (player *Player) positionAt(absTime float64) (float64, bool) {
+	position := player.anchorPosition + (absTime-player.anchorTime)*player.speed
+	duration := player.Duration()
+
+	switch {
+	case player.loop && duration > 0:
+		position = math.Mod(position, duration)
+		if position < 0 {
+			position += duration
+		}
+	case position < 0:
+		return 0, false
+	case position > duration:
+		return duration, false
+	}
+
+	return position, player.speed != 0
+}
+
+// Position returns how many seconds into the recording playback is.
+func (player *Player) Position() float64 {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	position, _ := player.positionAt(GetTimeInSeconds())
+	return position
+}
+
+// Seek continues playback from position seconds into the recording.
+func (player *Player) Seek(position float64) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	player.anchorTime = GetTimeInSeconds()
+	player.anchorPosition = position
+}
+
+// SetSpeed changes the playback speed, where 1 is the recorded speed and 0
+// pauses playback. Negative speeds play the recording backwards.
+func (player *Player) SetSpeed(speed float64) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	now := GetTimeInSeconds()
+	player.anchorPosition, _ = player.positionAt(now)
+	player.anchorTime = now
+	player.speed = speed
+}
+
+// SetLoop makes the playback start over when it reaches the end of the
+// recording, instead of holding the last tracking state.
+func (player *Player) SetLoop(loop bool) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	now := GetTimeInSeconds()
+	player.anchorPosition, _ = player.positionAt(now)
+	player.anchorTime = now
+	player.loop = loop
+}
+
+func lerpPose(a, b Posef, f float32) Posef {
+	return Posef{
+		Orientation: a.Orientation.Slerp(b.Orientation, f),
+		Position:    vectorLerp(a.Position, b.Position, f),
+	}
+}
+
+// Returns the recorded tracking state at absTime, interpolated between the two
+// nearest states. The status flags are those of the earlier state. Since the
+// playback speed changes how fast the head moves, the derivatives are scaled
+// to match, and so are the raw gyro and the accelerometer, less gravity, so
+// the raw sensor data agrees with the head pose.
+func (player *Player) trackingState(absTime float64) TrackingState {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	position, moving := player.positionAt(absTime)
+	recordedTime := player.states[0].HeadPose.TimeInSeconds + position
+
+	i := sort.Search(len(player.states), func(i int) bool {
+		return player.states[i].HeadPose.TimeInSeconds > recordedTime
+	})
+
+	a, b := player.states[max(i-1, 0)], player.states[min(i, len(player.states)-1)]
+
+	f := float32(0)
+	if span := b.HeadPose.TimeInSeconds - a.HeadPose.TimeInSeconds; span > 0 {
+		f = float32((recordedTime - a.HeadPose.TimeInSeconds) / span)
+	}
+
+	speed := float32(player.speed)
+	if !moving {
+		speed = 0
+	}
+
+	pose := lerpPose(a.HeadPose.ThePose, b.HeadPose.ThePose, f)
+	headPose := PoseStatef{
+		ThePose:             pose,
+		AngularVelocity:     vectorScale(vectorLerp(a.HeadPose.AngularVelocity, b.HeadPose.AngularVelocity, f), speed),
+		LinearVelocity:      vectorScale(vectorLerp(a.HeadPose.LinearVelocity, b.HeadPose.LinearVelocity, f), speed),
+		AngularAcceleration: vectorScale(vectorLerp(a.HeadPose.AngularAcceleration, b.HeadPose.AngularAcceleration, f), speed*speed),
+		LinearAcceleration:  vectorScale(vectorLerp(a.HeadPose.LinearAcceleration, b.HeadPose.LinearAcceleration, f), speed*speed),
+		TimeInSeconds:       absTime,
+	}
+
+	// The accelerometer measures gravity too, in the frame of the head, which
+	// doesn't depend on the speed.
+	gravity := pose.Orientation.Conjugate().Rotate(Vector3f{0, standardGravity, 0})
+	accelerometer := vectorLerp(a.RawSensorData.Accelerometer, b.RawSensorData.Accelerometer, f)
+
+	return TrackingState{
+		HeadPose:          headPose,
+		CameraPose:        lerpPose(a.CameraPose, b.CameraPose, f),
+		LeveledCameraPose: lerpPose(a.LeveledCameraPose, b.LeveledCameraPose, f),
+		RawSensorData: SensorData{
+			Accelerometer: vectorAdd(gravity, vectorScale(vectorSub(accelerometer, gravity), speed*speed)),
+			Gyro:          vectorScale(vectorLerp(a.RawSensorData.Gyro, b.RawSensorData.Gyro, f), speed),
+			Magnetometer:  vectorLerp(a.RawSensorData.Magnetometer, b.RawSensorData.Magnetometer, f),
+			Temperature:   a.RawSensorData.Temperature + (b.RawSensorData.Temperature-a.RawSensorData.Temperature)*f,
+			TimeInSeconds: float32(absTime),
+		},
+		StatusFlags: a.StatusFlags,
+	}
+}
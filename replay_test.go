@@ -0,0 +1,115 @@
+package ovr
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Records 5 seconds of the test motion script at 100Hz.
+func testRecording(t *testing.T) *bytes.Buffer {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+	hmd.Simulator().SetMotionScript(testMotionScript(), 10)
+
+	buf := &bytes.Buffer{}
+	rec, err := NewRecorder(hmd, buf)
+	if err != nil {
+		t.Fatalf("Expected NewRecorder() to succeed, instead of '%s'", err)
+	}
+
+	for i := 0; i <= 500; i++ {
+		hmd.GetTrackingState(10 + float64(i)*0.01)
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Expected Close() to succeed, instead of '%s'", err)
+	}
+
+	return buf
+}
+
+func TestHmdCreateReplay(t *testing.T) {
+	hmd, err := HmdCreateReplay(testRecording(t))
+	if err != nil {
+		t.Fatalf("Expected HmdCreateReplay() to succeed, instead of '%s'", err)
+	}
+	defer hmd.Destroy()
+
+	if hmd.ProductName != "Oculus Rift DK2" || hmd.Resolution != (Sizei{1920, 1080}) {
+		t.Error("Expected the descriptor to come from the recording")
+	}
+
+	player := hmd.Player()
+	if player == nil {
+		t.Fatal("Expected Player() to return the player")
+	}
+
+	if !approxFloat(5, float32(player.Duration()), 0.0001) {
+		t.Errorf("Expected a recording of 5 seconds, instead of %f", player.Duration())
+	}
+
+	// Pause the playback, so the time doesn't matter.
+	player.SetSpeed(0)
+
+	for _, position := range []float64{0.5, 1.234, 2.505, 3.5, 4.99} {
+		player.Seek(position)
+
+		expPose := testMotionScript().PoseState(position)
+		state := hmd.GetTrackingState(GetTimeInSeconds())
+		calcPose := state.HeadPose
+
+		if !approxFloat(expPose.ThePose.Orientation.Y, calcPose.ThePose.Orientation.Y, 0.001) || !approxFloat(expPose.ThePose.Position.X, calcPose.ThePose.Position.X, 0.001) {
+			t.Errorf("At %.3fs, expected pose %v, instead of %v", position, expPose.ThePose, calcPose.ThePose)
+		}
+
+		if calcPose.AngularVelocity != (Vector3f{}) || state.RawSensorData.Gyro != (Vector3f{}) {
+			t.Errorf("At %.3fs, expected no angular velocity while paused, instead of %v", position, calcPose.AngularVelocity)
+		}
+
+		gravity := calcPose.ThePose.Orientation.Conjugate().Rotate(Vector3f{0, standardGravity, 0})
+		if accel := state.RawSensorData.Accelerometer; !approxVector3f(gravity, accel, 0.001) {
+			t.Errorf("At %.3fs, expected the accelerometer to measure gravity alone while paused, instead of %v", position, accel)
+		}
+
+		if lost := position >= 3 && position < 4; lost != (state.StatusFlags&Status_PositionTracked == 0) {
+			t.Errorf("At %.3fs, unexpected status flags 0x%x", position, state.StatusFlags)
+		}
+	}
+
+	// At twice the speed, the gyro measures twice the angular velocity.
+	player.SetSpeed(2)
+	player.Seek(1.234)
+
+	expVelocity := vectorScale(testMotionScript().PoseState(1.234).AngularVelocity, 2)
+	if state := hmd.GetTrackingState(GetTimeInSeconds()); !approxVector3f(expVelocity, state.RawSensorData.Gyro, 0.01) || !approxVector3f(state.HeadPose.AngularVelocity, state.RawSensorData.Gyro, 0.001) {
+		t.Errorf("Expected the gyro to measure %v at twice the speed, instead of %v", expVelocity, state.RawSensorData.Gyro)
+	}
+	player.SetSpeed(0)
+
+	// Without looping playback holds at the end, with looping it wraps.
+	player.Seek(7)
+	if position := player.Position(); position != 5 {
+		t.Errorf("Expected playback to hold at the end, instead of at %f", position)
+	}
+
+	player.SetLoop(true)
+	player.Seek(7)
+	if position := player.Position(); !approxFloat(2, float32(position), 0.0001) {
+		t.Errorf("Expected playback to wrap around to 2s, instead of %f", position)
+	}
+}
+
+func TestHmdCreateReplayRejectsEmptyRecording(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	buf := &bytes.Buffer{}
+	rec, _ := NewRecorder(hmd, buf)
+	rec.Close()
+
+	if _, err := HmdCreateReplay(buf); err == nil {
+		t.Error("Expected HmdCreateReplay() to reject a recording without tracking states")
+	}
+}
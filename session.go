@@ -0,0 +1,165 @@
+package ovr
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// The number of open sessions. The SDK is initialized when the first one is
+// opened, and shut down when the last one is closed.
+var sessions struct {
+	sync.Mutex
+	count int
+}
+
+// A Session is a handle on the SDK that can be shared by several libraries in
+// the same program. Each of them opens its own session, and the SDK stays
+// initialized until the last one is closed. A session keeps track of the
+// devices created through it, and destroys them when it is closed.
+//
+// Calling Initialize() while sessions are open does nothing more, but
+// Shutdown() fails until the last of them is closed, so it can't pull the SDK
+// out from under them. Closing the last session shuts the SDK down, even if
+// Initialize() was called too.
+type Session struct {
+	mutex  sync.Mutex
+	hmds   map[*sessionDevice]*Hmd
+	closed bool
+}
+
+// A sessionDevice removes itself from its session when it is destroyed, so
+// the session doesn't destroy it a second time.
+type sessionDevice struct {
+	device
+	session *Session
+}
+
+func (dev *sessionDevice) unwrap() device {
+	return dev.device
+}
+
+func (dev *sessionDevice) rewrap(inner device) {
+	dev.device = inner
+}
+
+func (dev *sessionDevice) destroy() {
+	dev.session.mutex.Lock()
+	_, ok := dev.session.hmds[dev]
+	delete(dev.session.hmds, dev)
+	dev.session.mutex.Unlock()
+
+	if ok {
+		dev.device.destroy()
+	}
+}
+
+// NewSession opens a session, and initializes the SDK if no other session is
+// open.
+func NewSession() (*Session, error) {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	if sessions.count == 0 {
+		if err := currentBackend.initialize(); err != nil {
+			return nil, err
+		}
+	}
+
+	sessions.count++
+	return &Session{hmds: make(map[*sessionDevice]*Hmd)}, nil
+}
+
+// Close destroys every device that was created through the session and is
+// still open, and shuts down the SDK if this was the last open session.
+func (session *Session) Close() error {
+	session.mutex.Lock()
+	if session.closed {
+		session.mutex.Unlock()
+		return errors.New("the session is already closed")
+	}
+
+	session.closed = true
+	hmds := make([]*Hmd, 0, len(session.hmds))
+	for _, hmd := range session.hmds {
+		hmds = append(hmds, hmd)
+	}
+	session.mutex.Unlock()
+
+	for _, hmd := range hmds {
+		hmd.Destroy()
+	}
+
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	sessions.count--
+	if sessions.count == 0 {
+		currentBackend.shutdown()
+	}
+
+	return nil
+}
+
+// Takes ownership of a newly created device.
+func (session *Session) track(hmd *Hmd) (*Hmd, error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if session.closed {
+		hmd.Destroy()
+		return nil, errors.New("the session is closed")
+	}
+
+	dev := &sessionDevice{device: hmd.dev, session: session}
+	hmd.dev = dev
+	session.hmds[dev] = hmd
+
+	return hmd, nil
+}
+
+func (session *Session) isClosed() bool {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	return session.closed
+}
+
+// HmdDetect returns the number of attached devices.
+func (session *Session) HmdDetect() (int, error) {
+	if session.isClosed() {
+		return 0, errors.New("the session is closed")
+	}
+
+	return currentBackend.detect(), nil
+}
+
+// HmdCreate opens the device at index. The device is destroyed when the
+// session is closed, unless it is destroyed before.
+func (session *Session) HmdCreate(index int) (*Hmd, error) {
+	if session.isClosed() {
+		return nil, errors.New("the session is closed")
+	}
+
+	hmd := currentBackend.create(index)
+	if hmd == nil {
+		return nil, fmt.Errorf("failed to open the device at index %d", index)
+	}
+
+	return session.track(hmd)
+}
+
+// HmdCreateDebug creates a debug device of the given type. The device is
+// destroyed when the session is closed, unless it is destroyed before.
+func (session *Session) HmdCreateDebug(hmdType HmdType) (*Hmd, error) {
+	if session.isClosed() {
+		return nil, errors.New("the session is closed")
+	}
+
+	hmd := currentBackend.createDebug(hmdType)
+	if hmd == nil {
+		return nil, fmt.Errorf("failed to create a debug device of type %d", hmdType)
+	}
+
+	return session.track(hmd)
+}
@@ -0,0 +1,83 @@
+package ovr
+
+import "testing"
+
+func TestSessionLifecycle(t *testing.T) {
+	first, err := NewSession()
+	if err != nil {
+		t.Fatalf("Expected NewSession() to succeed, instead of '%s'", err)
+	}
+
+	second, err := NewSession()
+	if err != nil {
+		t.Fatalf("Expected a second NewSession() to succeed, instead of '%s'", err)
+	}
+
+	if sessions.count != 2 {
+		t.Errorf("Expected 2 open sessions, instead of %d", sessions.count)
+	}
+
+	if err := Shutdown(); err == nil {
+		t.Error("Expected Shutdown() to fail while sessions are open")
+	}
+
+	if err := first.Close(); err != nil {
+		t.Errorf("Expected Close() to succeed, instead of '%s'", err)
+	}
+
+	if err := first.Close(); err == nil {
+		t.Error("Expected closing a session twice to fail")
+	}
+
+	if _, err := first.HmdCreateDebug(Hmd_DK2); err == nil {
+		t.Error("Expected HmdCreateDebug() to fail on a closed session")
+	}
+
+	if _, err := second.HmdCreateDebug(Hmd_DK2); err != nil {
+		t.Errorf("Expected HmdCreateDebug() to succeed on an open session, instead of '%s'", err)
+	}
+
+	if err := second.Close(); err != nil {
+		t.Errorf("Expected Close() to succeed, instead of '%s'", err)
+	}
+
+	if sessions.count != 0 {
+		t.Errorf("Expected no open sessions, instead of %d", sessions.count)
+	}
+
+	if err := Shutdown(); err != nil {
+		t.Errorf("Expected Shutdown() to succeed once the sessions are closed, instead of '%s'", err)
+	}
+}
+
+func TestSessionDestroysDevices(t *testing.T) {
+	session, err := NewSession()
+	if err != nil {
+		t.Fatalf("Expected NewSession() to succeed, instead of '%s'", err)
+	}
+
+	kept, _ := session.HmdCreateDebug(Hmd_DK2)
+	destroyed, _ := session.HmdCreateDebug(Hmd_DK1)
+
+	if kept.Simulator() == nil || destroyed.Simulator() == nil {
+		session.Close()
+		t.Skip("The debug devices of this backend aren't simulated")
+	}
+
+	destroyCount := map[*Hmd]int{}
+	for _, hmd := range []*Hmd{kept, destroyed} {
+		hmd.Simulator().onDestroy = func() { destroyCount[hmd]++ }
+	}
+
+	destroyed.Destroy()
+
+	if len(session.hmds) != 1 {
+		t.Errorf("Expected the session to forget the destroyed device, instead of tracking %d devices", len(session.hmds))
+	}
+
+	session.Close()
+
+	if destroyCount[kept] != 1 || destroyCount[destroyed] != 1 {
+		t.Errorf("Expected each device to be destroyed once, instead of %d and %d", destroyCount[kept], destroyCount[destroyed])
+	}
+}
@@ -0,0 +1,821 @@
+package ovr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// ****************************************************************************
+// ************************** [ Simulated profiles ] **************************
+// ****************************************************************************
+
+// A simProfile describes the hardware of a simulated device, much like the
+// HMDInfo structure the SDK fills in for its debug devices.
+type simProfile struct {
+	desc                      Hmd
+	pixelsPerTanAngleAtCenter float32
+	refreshRate               float64
+}
+
+// The debug devices of the SDK share the same identity and capabilities, and
+// only differ in their display and tracking hardware.
+func newSimProfile(hmdType HmdType) (simProfile, bool) {
+	desc := Hmd{
+		Type:           hmdType,
+		Manufacturer:   "Oculus VR",
+		VendorId:       0x2833,
+		HmdCaps:        HmdCap_Present | HmdCap_Available | HmdCap_LowPersistence | HmdCap_DynamicPrediction | HmdCap_NoVSync,
+		TrackingCaps:   TrackingCap_Orientation | TrackingCap_MagYawCorrection,
+		DistortionCaps: DistortionCap_Chromatic | DistortionCap_TimeWarp | DistortionCap_Vignette | DistortionCap_NoRestore | DistortionCap_FlipInput | DistortionCap_SRGB | DistortionCap_ProfileNoTimewarpSpinWaits,
+		EyeRenderOrder: [Eye_Count]EyeType{Eye_Left, Eye_Right},
+		DisplayId:      -1,
+	}
+
+	profile := simProfile{refreshRate: 60}
+
+	switch hmdType {
+	case Hmd_DK1:
+		desc.ProductName = "Oculus Rift DK1"
+		desc.ProductId = 0x0001
+		desc.Resolution = Sizei{1280, 800}
+		desc.DefaultEyeFov = mirroredFov(FovPort{UpTan: 1.3787, DownTan: 1.3787, LeftTan: 1.0540, RightTan: 0.9389})
+		desc.MaxEyeFov = mirroredFov(FovPort{UpTan: 1.4138, DownTan: 1.4138, LeftTan: 1.0808, RightTan: 0.9628})
+		profile.pixelsPerTanAngleAtCenter = 317.5
+
+	case Hmd_DKHD:
+		desc.ProductName = "Oculus Rift DK HD"
+		desc.ProductId = 0x0001
+		desc.Resolution = Sizei{1920, 1080}
+		desc.DefaultEyeFov = mirroredFov(FovPort{UpTan: 1.1917, DownTan: 1.1917, LeftTan: 1.0584, RightTan: 0.9736})
+		desc.MaxEyeFov = mirroredFov(FovPort{UpTan: 1.2220, DownTan: 1.2220, LeftTan: 1.0853, RightTan: 0.9983})
+		profile.pixelsPerTanAngleAtCenter = 483.0
+
+	case Hmd_DK2:
+		desc.ProductName = "Oculus Rift DK2"
+		desc.ProductId = 0x0021
+		desc.Resolution = Sizei{1920, 1080}
+		desc.CameraFrustumHFovInRadians = float32(74.0 * math.Pi / 180.0)
+		desc.CameraFrustumVFovInRadians = float32(54.0 * math.Pi / 180.0)
+		desc.CameraFrustumNearZInMeters = 0.4
+		desc.CameraFrustumFarZInMeters = 2.5
+		desc.TrackingCaps |= TrackingCap_Position
+		desc.DistortionCaps |= DistortionCap_Overdrive
+		desc.DefaultEyeFov = mirroredFov(FovPort{UpTan: 1.3292863, DownTan: 1.3292863, LeftTan: 1.0586576, RightTan: 1.092368})
+		desc.MaxEyeFov = desc.DefaultEyeFov
+
+		// The DK2 has a rolling shutter that scans out from right to left, so
+		// the right eye should be rendered first.
+		desc.EyeRenderOrder = [Eye_Count]EyeType{Eye_Right, Eye_Left}
+
+		profile.pixelsPerTanAngleAtCenter = 549.5
+		profile.refreshRate = 75
+
+	default:
+		return simProfile{}, false
+	}
+
+	profile.desc = desc
+	return profile, true
+}
+
+// Returns the FOV of both eyes, where fov is that of the left eye.
+func mirroredFov(fov FovPort) [Eye_Count]FovPort {
+	return [Eye_Count]FovPort{
+		fov,
+		{UpTan: fov.UpTan, DownTan: fov.DownTan, LeftTan: fov.RightTan, RightTan: fov.LeftTan},
+	}
+}
+
+// ****************************************************************************
+// *************************** [ Simulated device ] ***************************
+// ****************************************************************************
+
+// A Simulator is a pure-Go device that behaves like the debug devices of the
+// SDK. Unless it is given a MotionScript, it holds still at the origin and
+// never loses tracking.
+type Simulator struct {
+	mutex   sync.Mutex
+	profile simProfile
+
+	script      *MotionScript
+	scriptStart float64
+	player      *Player
+
+	lastErr      *string
+	hmdCaps      uint
+	trackingCaps uint
+	recenter     Posef
+
+	frameIndex uint
+	timing     FrameTiming
+
+	hswStartTime float64
+	hswDismissed bool
+
+	properties map[string]interface{}
+
+	faults              []simFault
+	calls               simCalls
+	lastTrackedPosition Vector3f
+	rand                *rand.Rand
+
+	// Called once when the device is destroyed, if set.
+	onDestroy func()
+}
+
+// HmdCreateSimulated creates a simulated device of the given type. This works
+// with every backend, so it can be used alongside real hardware. It returns
+// nil if the type isn't one of Hmd_DK1, Hmd_DKHD or Hmd_DK2.
+func HmdCreateSimulated(hmdType HmdType) *Hmd {
+	profile, ok := newSimProfile(hmdType)
+	if !ok {
+		return nil
+	}
+
+	return newSimulator(profile).hmd()
+}
+
+// A simAttachment is a simulated device that shows up in HmdDetect().
+type simAttachment struct {
+	profile  simProfile
+	captured bool
+}
+
+var simAttached struct {
+	sync.Mutex
+	devices []*simAttachment
+}
+
+// AttachSimulatedHmd makes a simulated device of the given type show up as if
+// it were plugged in, so it is counted by HmdDetect(), described by Devices()
+// and opened with HmdCreate(), and returns its index. It fails if the type
+// isn't one of Hmd_DK1, Hmd_DKHD or Hmd_DK2, or if the package is built with
+// the libovr build tag, where the SDK reports the attached hardware. Use
+// HmdCreateSimulated() there.
+func AttachSimulatedHmd(hmdType HmdType) (int, error) {
+	if !simulatedHardware {
+		return -1, errors.New("simulated devices can't be attached with the libovr backend")
+	}
+
+	profile, ok := newSimProfile(hmdType)
+	if !ok {
+		return -1, fmt.Errorf("unsupported simulated device type %d", hmdType)
+	}
+
+	simAttached.Lock()
+	defer simAttached.Unlock()
+
+	index := len(simAttached.devices)
+	profile.desc.SerialNumber = fmt.Sprintf("SIM%09d", index+1)
+
+	switch hmdType {
+	case Hmd_DK2:
+		profile.desc.FirmwareMajor, profile.desc.FirmwareMinor = 2, 12
+	default:
+		profile.desc.FirmwareMajor, profile.desc.FirmwareMinor = 0, 18
+	}
+
+	simAttached.devices = append(simAttached.devices, &simAttachment{profile: profile})
+	return index, nil
+}
+
+// DetachSimulatedHmds unplugs every device attached with AttachSimulatedHmd().
+// Devices that are open keep working until they are destroyed.
+func DetachSimulatedHmds() {
+	simAttached.Lock()
+	defer simAttached.Unlock()
+
+	simAttached.devices = nil
+}
+
+func newSimulator(profile simProfile) *Simulator {
+	return &Simulator{
+		profile:      profile,
+		recenter:     Posef{Orientation: Quatf{W: 1}},
+		hswStartTime: GetTimeInSeconds(),
+		properties:   make(map[string]interface{}),
+	}
+}
+
+// Returns a new Hmd for the simulated device.
+func (sim *Simulator) hmd() *Hmd {
+	hmd := sim.profile.desc
+	hmd.dev = sim
+	return &hmd
+}
+
+// Simulator returns the simulated device behind hmd, or nil if hmd is not a
+// simulated device.
+func (hmd *Hmd) Simulator() *Simulator {
+	sim, _ := baseDevice(hmd.dev).(*Simulator)
+	return sim
+}
+
+// SetMotionScript makes the device follow script, starting at absTime. Pass a
+// nil script to make the device hold still again. It fails, and leaves the
+// device as it was, if the script is invalid.
+func (sim *Simulator) SetMotionScript(script *MotionScript, absTime float64) error {
+	if script != nil {
+		if err := script.validate(); err != nil {
+			return err
+		}
+	}
+
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.script = script
+	sim.scriptStart = absTime
+
+	return nil
+}
+
+func (sim *Simulator) setLastError(err string) {
+	sim.lastErr = &err
+}
+
+func (sim *Simulator) destroy() {
+	sim.mutex.Lock()
+	onDestroy := sim.onDestroy
+	sim.onDestroy = nil
+	sim.mutex.Unlock()
+
+	if onDestroy != nil {
+		onDestroy()
+	}
+}
+
+func (sim *Simulator) lastError() *string {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	return sim.lastErr
+}
+
+func (sim *Simulator) enabledCaps() uint {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	return sim.hmdCaps
+}
+
+func (sim *Simulator) setEnabledCaps(hmdCaps uint) {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.hmdCaps = hmdCaps & HmdCap_Writable_Mask
+}
+
+// ****************************************************************************
+// ************************** [ Tracking interface ] **************************
+// ****************************************************************************
+
+func (sim *Simulator) configureTracking(supportedTrackingCaps uint, requiredTrackingCaps uint) bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	if requiredTrackingCaps&^(sim.profile.desc.TrackingCaps|TrackingCap_Idle) != 0 {
+		sim.setLastError("Required tracking capabilities are not supported by this device")
+		return false
+	}
+
+	sim.trackingCaps = supportedTrackingCaps & (sim.profile.desc.TrackingCaps | TrackingCap_Idle)
+	return true
+}
+
+// Recentering removes the current yaw and position from subsequent poses.
+func (sim *Simulator) recenterPose() {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	pose := sim.rawTrackingState(GetTimeInSeconds()).HeadPose.ThePose
+	yaw, _, _ := pose.Orientation.YawPitchRoll()
+
+	sim.recenter = Posef{
+		Orientation: QuatfFromAxisAngle(Vector3f{0, 1, 0}, -yaw),
+		Position:    pose.Position,
+	}
+}
+
+func (sim *Simulator) trackingState(absTime float64) TrackingState {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.calls.trackingState++
+	return sim.trackingStateAt(absTime)
+}
+
+// The pose of the device, before recentering is applied.
+func (sim *Simulator) rawPoseState(absTime float64) PoseStatef {
+	if sim.script == nil {
+		return PoseStatef{
+			ThePose:       Posef{Orientation: Quatf{W: 1}},
+			TimeInSeconds: absTime,
+		}
+	}
+
+	poseState := sim.script.PoseState(absTime - sim.scriptStart)
+	poseState.TimeInSeconds = absTime
+
+	return poseState
+}
+
+// The status bits the motion script says are lost at absTime.
+func (sim *Simulator) lostStatus(absTime float64) uint {
+	if sim.script == nil {
+		return 0
+	}
+
+	lost, _ := sim.script.lostStatus(absTime - sim.scriptStart)
+	return lost
+}
+
+// The tracking state of the device, before recentering is applied.
+func (sim *Simulator) rawTrackingState(absTime float64) TrackingState {
+	if sim.player != nil {
+		return sim.player.trackingState(absTime)
+	}
+
+	poseState := sim.rawPoseState(absTime)
+	state := TrackingState{
+		HeadPose:      poseState,
+		RawSensorData: sim.sensorData(poseState),
+		StatusFlags:   Status_HmdConnected,
+	}
+
+	if sim.trackingCaps&TrackingCap_Orientation != 0 {
+		state.StatusFlags |= Status_OrientationTracked
+	}
+
+	// The camera sits a meter in front of the origin, looking back at it.
+	if sim.trackingCaps&TrackingCap_Position != 0 {
+		state.StatusFlags |= Status_PositionConnected | Status_PositionTracked | Status_CameraPoseTracked
+		state.CameraPose = Posef{Orientation: Quatf{Y: 1}, Position: Vector3f{0, 0, -1}}
+		state.LeveledCameraPose = state.CameraPose
+	}
+
+	state.StatusFlags &^= sim.lostStatus(absTime)
+	return state
+}
+
+func (sim *Simulator) recentered(pose Posef) Posef {
+	return Posef{
+		Orientation: sim.recenter.Orientation.Mul(pose.Orientation),
+		Position:    sim.recenter.Orientation.Rotate(vectorSub(pose.Position, sim.recenter.Position)),
+	}
+}
+
+// Apply the recentering to the poses and the linear derivatives. The angular
+// ones are in the frame of the head, so they stay as they are.
+func (sim *Simulator) trackingStateAt(absTime float64) TrackingState {
+	state := sim.applyFaults(sim.rawTrackingState(absTime), absTime)
+
+	headPose := &state.HeadPose
+	headPose.ThePose = sim.recentered(headPose.ThePose)
+	headPose.LinearVelocity = sim.recenter.Orientation.Rotate(headPose.LinearVelocity)
+	headPose.LinearAcceleration = sim.recenter.Orientation.Rotate(headPose.LinearAcceleration)
+
+	if state.StatusFlags&Status_PositionConnected != 0 {
+		state.CameraPose = sim.recentered(state.CameraPose)
+		state.LeveledCameraPose = sim.recentered(state.LeveledCameraPose)
+	}
+
+	return state
+}
+
+// The Earth's magnetic field in gauss, in the frame of the tracking origin.
+var simMagneticField = Vector3f{0.0, -0.42, -0.21}
+
+// Returns the readings the IMU would produce for the given pose. The sensors
+// are read in the frame of the device.
+func (sim *Simulator) sensorData(poseState PoseStatef) SensorData {
+	toDevice := poseState.ThePose.Orientation.Conjugate()
+	specificForce := vectorAdd(poseState.LinearAcceleration, Vector3f{0, 9.80665, 0})
+
+	return SensorData{
+		Accelerometer: toDevice.Rotate(specificForce),
+		Gyro:          poseState.AngularVelocity,
+		Magnetometer:  toDevice.Rotate(simMagneticField),
+		Temperature:   35.0,
+		TimeInSeconds: float32(poseState.TimeInSeconds),
+	}
+}
+
+// ****************************************************************************
+// **************************** [ Graphics setup ] ****************************
+// ****************************************************************************
+
+func (sim *Simulator) fovTextureSize(eye EyeType, fov FovPort, pixelsPerDisplayPixel float32) Sizei {
+	pixels := pixelsPerDisplayPixel * sim.profile.pixelsPerTanAngleAtCenter
+
+	return Sizei{
+		W: int(0.5 + pixels*(fov.LeftTan+fov.RightTan)),
+		H: int(0.5 + pixels*(fov.UpTan+fov.DownTan)),
+	}
+}
+
+// ****************************************************************************
+// *********************** [ SDK Distortion rendering ] ***********************
+// ****************************************************************************
+
+func (sim *Simulator) configureRendering(apiConfig *RenderAPIConfig, distortionCaps uint, eyeFovIn [2]FovPort) ([2]EyeRenderDesc, bool) {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.calls.configureRendering++
+	if sim.failCall(Fault_ConfigureRendering) {
+		return [2]EyeRenderDesc{}, false
+	}
+
+	if apiConfig == nil || apiConfig.Header.API != RenderAPI_OpenGL {
+		sim.setLastError("Unsupported render API")
+		return [2]EyeRenderDesc{}, false
+	}
+
+	for eye := 0; eye < Eye_Count; eye++ {
+		if !validFov(eyeFovIn[eye]) {
+			sim.setLastError("Invalid eye FOV")
+			return [2]EyeRenderDesc{}, false
+		}
+	}
+
+	return [2]EyeRenderDesc{
+		sim.renderDescFor(Eye_Left, eyeFovIn[0]),
+		sim.renderDescFor(Eye_Right, eyeFovIn[1]),
+	}, true
+}
+
+func validFov(fov FovPort) bool {
+	return fov.LeftTan+fov.RightTan > 0 && fov.UpTan+fov.DownTan > 0
+}
+
+func (sim *Simulator) beginFrame(frameIndex uint) FrameTiming {
+	return sim.beginFrameTiming(frameIndex)
+}
+
+func (sim *Simulator) endFrame(renderPose [2]Posef, eyeTexture [2]Texture) {
+	sim.endFrameTiming()
+}
+
+func (sim *Simulator) eyePose(eye EyeType) Posef {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	absTime := GetTimeInSeconds()
+	if sim.timing.ThisFrameSeconds != 0 {
+		absTime = sim.timing.EyeScanoutSeconds[eye]
+	}
+
+	return sim.trackingStateAt(absTime).HeadPose.ThePose
+}
+
+// ****************************************************************************
+// ********************** [ Client Distortion rendering ] *********************
+// ****************************************************************************
+
+func (sim *Simulator) renderDesc(eye EyeType, fov FovPort) EyeRenderDesc {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	return sim.renderDescFor(eye, fov)
+}
+
+// Each eye gets its own half of the screen, and is offset by half the IPD.
+// The view adjustment is applied to the view matrix, so it points the other
+// way from the eye itself.
+func (sim *Simulator) renderDescFor(eye EyeType, fov FovPort) EyeRenderDesc {
+	resolution := sim.profile.desc.Resolution
+	halfIPD := sim.floatProperty(KEY_IPD, DEFAULT_IPD) / 2
+
+	desc := EyeRenderDesc{
+		Eye:                       eye,
+		Fov:                       fov,
+		DistortedViewport:         Recti{Size: Sizei{resolution.W / 2, resolution.H}},
+		PixelsPerTanAngleAtCenter: Vector2f{sim.profile.pixelsPerTanAngleAtCenter, sim.profile.pixelsPerTanAngleAtCenter},
+		ViewAdjust:                Vector3f{X: halfIPD},
+	}
+
+	if eye == Eye_Right {
+		desc.DistortedViewport.Pos.X = resolution.W / 2
+		desc.ViewAdjust.X = -halfIPD
+	}
+
+	return desc
+}
+
+// The number of quads along each side of the distortion mesh.
+const simMeshResolution = 16
+
+// The mesh uses a simple radial distortion model. Chromatic aberration is
+// simulated by scaling the red and blue channels slightly.
+func (sim *Simulator) createDistortionMesh(eye EyeType, fov FovPort, distortionCaps uint) (*DistortionMesh, bool) {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.calls.createDistortionMesh++
+	if sim.failCall(Fault_CreateDistortionMesh) {
+		return nil, false
+	}
+
+	if !validFov(fov) {
+		sim.setLastError("Invalid eye FOV")
+		return nil, false
+	}
+
+	ndc := ndcScaleAndOffsetFromFov(fov)
+	meshData := &DistortionMesh{}
+
+	for y := 0; y <= simMeshResolution; y++ {
+		for x := 0; x <= simMeshResolution; x++ {
+			screen := Vector2f{
+				2*float32(x)/simMeshResolution - 1,
+				2*float32(y)/simMeshResolution - 1,
+			}
+
+			tan := Vector2f{
+				(screen.X - ndc.Offset.X) / ndc.Scale.X,
+				(screen.Y - ndc.Offset.Y) / ndc.Scale.Y,
+			}
+
+			rSq := tan.X*tan.X + tan.Y*tan.Y
+			distortion := 1 + 0.22*rSq + 0.24*rSq*rSq
+			green := Vector2f{tan.X * distortion, tan.Y * distortion}
+
+			vertex := DistortionVertex{
+				ScreenPosNDC:   screen,
+				TimeWarpFactor: float32(x) / simMeshResolution,
+				VignetteFactor: 1,
+				TanEyeAnglesR:  green,
+				TanEyeAnglesG:  green,
+				TanEyeAnglesB:  green,
+			}
+
+			if distortionCaps&DistortionCap_Chromatic != 0 {
+				vertex.TanEyeAnglesR = Vector2f{green.X * 0.994, green.Y * 0.994}
+				vertex.TanEyeAnglesB = Vector2f{green.X * 1.014, green.Y * 1.014}
+			}
+
+			if distortionCaps&DistortionCap_Vignette != 0 {
+				edge := float32(math.Max(math.Abs(float64(screen.X)), math.Abs(float64(screen.Y))))
+				vertex.VignetteFactor = float32(math.Min(1, float64(1-edge)*10))
+			}
+
+			meshData.VertexData = append(meshData.VertexData, vertex)
+		}
+	}
+
+	for y := 0; y < simMeshResolution; y++ {
+		for x := 0; x < simMeshResolution; x++ {
+			i := uint16(y*(simMeshResolution+1) + x)
+			next := i + simMeshResolution + 1
+			meshData.IndexData = append(meshData.IndexData, i, next, i+1, i+1, next, next+1)
+		}
+	}
+
+	return meshData, true
+}
+
+// A frame starts at vsync and is scanned out during the next refresh
+// interval. With a rolling shutter, the first eye in EyeRenderOrder is
+// scanned out in the first half of that interval.
+func (sim *Simulator) timingFor(thisFrameSeconds float64, deltaSeconds float32) FrameTiming {
+	frameDelta := 1.0 / sim.profile.refreshRate
+	nextFrameSeconds := thisFrameSeconds + frameDelta
+
+	timing := FrameTiming{
+		DeltaSeconds:           deltaSeconds,
+		ThisFrameSeconds:       thisFrameSeconds,
+		TimewarpPointSeconds:   nextFrameSeconds - 0.002,
+		NextFrameSeconds:       nextFrameSeconds,
+		ScanoutMidpointSeconds: nextFrameSeconds + frameDelta*0.5,
+	}
+
+	order := sim.profile.desc.EyeRenderOrder
+	timing.EyeScanoutSeconds[order[0]] = nextFrameSeconds + frameDelta*0.25
+	timing.EyeScanoutSeconds[order[1]] = nextFrameSeconds + frameDelta*0.75
+
+	return timing
+}
+
+func (sim *Simulator) frameTiming(frameIndex uint) FrameTiming {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	if sim.timing.ThisFrameSeconds == 0 {
+		return sim.timingFor(GetTimeInSeconds(), 0)
+	}
+
+	frameDelta := 1.0 / sim.profile.refreshRate
+	thisFrameSeconds := sim.timing.ThisFrameSeconds + (float64(frameIndex)-float64(sim.frameIndex))*frameDelta
+
+	return sim.timingFor(thisFrameSeconds, float32(frameDelta))
+}
+
+func (sim *Simulator) beginFrameTiming(frameIndex uint) FrameTiming {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	now := GetTimeInSeconds()
+
+	var deltaSeconds float32
+	if sim.timing.ThisFrameSeconds != 0 {
+		deltaSeconds = float32(now - sim.timing.ThisFrameSeconds)
+	}
+
+	sim.frameIndex = frameIndex
+	sim.timing = sim.timingFor(now, deltaSeconds)
+
+	return sim.timing
+}
+
+func (sim *Simulator) endFrameTiming() {}
+
+func (sim *Simulator) resetFrameTiming(frameIndex uint) {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.frameIndex = frameIndex
+	sim.timing = FrameTiming{}
+}
+
+// The timewarp matrices rotate from the pose the eye was rendered with to the
+// pose predicted at the start and end of the eye's scanout.
+func (sim *Simulator) eyeTimewarpMatrices(eye EyeType, renderPose Posef) [2]Matrix4f {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	scanoutStart := GetTimeInSeconds()
+	if sim.timing.ThisFrameSeconds != 0 {
+		scanoutStart = sim.timing.EyeScanoutSeconds[eye]
+	}
+	scanoutEnd := scanoutStart + 0.5/sim.profile.refreshRate
+
+	renderFromEye := renderPose.Orientation.Conjugate()
+	twmOut := [2]Matrix4f{}
+
+	for i, absTime := range [2]float64{scanoutStart, scanoutEnd} {
+		predicted := sim.trackingStateAt(absTime).HeadPose.ThePose.Orientation
+		twmOut[i] = Matrix4f_Rotation(renderFromEye.Mul(predicted))
+	}
+
+	return twmOut
+}
+
+// ****************************************************************************
+// ************************ [ Latency Test interface ] ************************
+// ****************************************************************************
+
+// There is no latency tester attached to a simulated device.
+func (sim *Simulator) processLatencyTest() (*[3]uint, bool) {
+	return nil, false
+}
+
+func (sim *Simulator) latencyTestResult() *string {
+	return nil
+}
+
+// ****************************************************************************
+// ************** [ Health and Safety Warning Display interface ] *************
+// ****************************************************************************
+
+// The number of seconds the Health and Safety Warning is shown before it can
+// be dismissed.
+const simHSWDismissDelay = 15.0
+
+func (sim *Simulator) hswDisplayState() HSWDisplayState {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	return HSWDisplayState{
+		Displayed:       !sim.hswDismissed,
+		StartTime:       sim.hswStartTime,
+		DismissibleTime: sim.hswStartTime + simHSWDismissDelay,
+	}
+}
+
+func (sim *Simulator) dismissHSWDisplay() bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	if sim.hswDismissed || GetTimeInSeconds() < sim.hswStartTime+simHSWDismissDelay {
+		return false
+	}
+
+	sim.hswDismissed = true
+	return true
+}
+
+// ****************************************************************************
+// **************************** [ Property Access ] ***************************
+// ****************************************************************************
+
+func (sim *Simulator) floatProperty(propertyName string, defaultVal float32) float32 {
+	if value, ok := sim.properties[propertyName].(float32); ok {
+		return value
+	}
+
+	return defaultVal
+}
+
+func (sim *Simulator) getBool(propertyName string, defaultVal bool) bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	if value, ok := sim.properties[propertyName].(bool); ok {
+		return value
+	}
+
+	return defaultVal
+}
+
+func (sim *Simulator) setBool(propertyName string, value bool) bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.properties[propertyName] = value
+	return true
+}
+
+func (sim *Simulator) getInt(propertyName string, defaultVal int) int {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	if value, ok := sim.properties[propertyName].(int); ok {
+		return value
+	}
+
+	return defaultVal
+}
+
+func (sim *Simulator) setInt(propertyName string, value int) bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.properties[propertyName] = value
+	return true
+}
+
+func (sim *Simulator) getFloat(propertyName string, defaultVal float32) float32 {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	return sim.floatProperty(propertyName, defaultVal)
+}
+
+func (sim *Simulator) setFloat(propertyName string, value float32) bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.properties[propertyName] = value
+	return true
+}
+
+func (sim *Simulator) getFloatArray(propertyName string, values []float32, arraySize uint) uint {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	stored, ok := sim.properties[propertyName].([]float32)
+	if !ok {
+		return 0
+	}
+
+	if arraySize > uint(len(values)) {
+		arraySize = uint(len(values))
+	}
+
+	return uint(copy(values[:arraySize], stored))
+}
+
+func (sim *Simulator) setFloatArray(propertyName string, values []float32, arraySize uint) bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	if arraySize > uint(len(values)) {
+		arraySize = uint(len(values))
+	}
+
+	sim.properties[propertyName] = append([]float32(nil), values[:arraySize]...)
+	return true
+}
+
+func (sim *Simulator) getString(propertyName, defaultVal string) string {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	if value, ok := sim.properties[propertyName].(string); ok {
+		return value
+	}
+
+	return defaultVal
+}
+
+func (sim *Simulator) setString(propertyName, value string) bool {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+
+	sim.properties[propertyName] = value
+	return true
+}
@@ -0,0 +1,85 @@
+package ovr
+
+import "testing"
+
+func TestHmdCreateSimulated(t *testing.T) {
+	for _, hmdType := range []HmdType{Hmd_DK1, Hmd_DKHD, Hmd_DK2} {
+		hmd := HmdCreateSimulated(hmdType)
+		if hmd == nil {
+			t.Fatalf("Expected HmdCreateSimulated(%d) to return a device", hmdType)
+		}
+
+		if hmd.Type != hmdType {
+			t.Errorf("Expected a device of type %d, instead of %d", hmdType, hmd.Type)
+		}
+
+		if hmd.ProductName == "" || hmd.Resolution.W == 0 || hmd.DefaultEyeFov[0].LeftTan == 0 {
+			t.Errorf("Expected the descriptor of device type %d to be filled in", hmdType)
+		}
+
+		hmd.Destroy()
+	}
+
+	if HmdCreateSimulated(Hmd_Other) != nil {
+		t.Error("Expected HmdCreateSimulated(Hmd_Other) to return nil")
+	}
+}
+
+func TestSimulatorTrackingStatus(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	if flags := hmd.GetTrackingState(0).StatusFlags; flags != Status_HmdConnected {
+		t.Errorf("Expected only Status_HmdConnected before tracking is configured, instead of 0x%x", flags)
+	}
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+
+	expFlags := uint(Status_HmdConnected | Status_OrientationTracked | Status_PositionConnected | Status_PositionTracked | Status_CameraPoseTracked)
+	if flags := hmd.GetTrackingState(0).StatusFlags; flags != expFlags {
+		t.Errorf("Expected status flags 0x%x, instead of 0x%x", expFlags, flags)
+	}
+
+	dk1 := HmdCreateSimulated(Hmd_DK1)
+	defer dk1.Destroy()
+
+	if dk1.ConfigureTracking(TrackingCap_Orientation, TrackingCap_Position) {
+		t.Error("Expected ConfigureTracking() to fail when requiring position tracking on a DK1")
+	}
+
+	if dk1.GetLastError() == nil {
+		t.Error("Expected GetLastError() to explain why ConfigureTracking() failed")
+	}
+}
+
+func TestSimulatorSensorData(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	if accel := hmd.GetTrackingState(0).RawSensorData.Accelerometer; !approxFloat(9.80665, accel.Y, 0.0001) {
+		t.Errorf("Expected the accelerometer to measure gravity along Y, instead of %f", accel.Y)
+	}
+}
+
+func TestSimulatorConfigureRendering(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	renderConfig := GLConfig{}
+	renderConfig.OGL.Header.API = RenderAPI_D311
+
+	if _, err := hmd.ConfigureRendering(renderConfig.Config(), hmd.DistortionCaps, hmd.DefaultEyeFov); err == nil {
+		t.Error("Expected ConfigureRendering() to reject a D3D11 configuration")
+	}
+
+	renderConfig.OGL.Header.API = RenderAPI_OpenGL
+
+	eyeRenderDesc, err := hmd.ConfigureRendering(renderConfig.Config(), hmd.DistortionCaps, hmd.DefaultEyeFov)
+	if err != nil {
+		t.Fatalf("Expected ConfigureRendering() to succeed, instead of '%s'", err)
+	}
+
+	if eyeRenderDesc[0].ViewAdjust.X <= 0 || eyeRenderDesc[1].ViewAdjust.X >= 0 {
+		t.Error("Expected the view adjustment of the eyes to point away from each other")
+	}
+}
@@ -0,0 +1,189 @@
+/* Test stub of OVR_CAPI.h from the Oculus SDK 0.4.1. It only declares the
+   types the ovr package uses, following the layout of the real header. The
+   functions are declared by libovr_loader.h. */
+#ifndef OVR_CAPI_h
+#define OVR_CAPI_h
+
+#include <stdint.h>
+
+typedef char ovrBool;
+
+typedef struct ovrVector2i_ { int x, y; } ovrVector2i;
+typedef struct ovrSizei_ { int w, h; } ovrSizei;
+typedef struct ovrRecti_ { ovrVector2i Pos; ovrSizei Size; } ovrRecti;
+typedef struct ovrQuatf_ { float x, y, z, w; } ovrQuatf;
+typedef struct ovrVector2f_ { float x, y; } ovrVector2f;
+typedef struct ovrVector3f_ { float x, y, z; } ovrVector3f;
+typedef struct ovrMatrix4f_ { float M[4][4]; } ovrMatrix4f;
+typedef struct ovrPosef_ { ovrQuatf Orientation; ovrVector3f Position; } ovrPosef;
+
+typedef struct ovrPoseStatef_ {
+	ovrPosef ThePose;
+	ovrVector3f AngularVelocity;
+	ovrVector3f LinearVelocity;
+	ovrVector3f AngularAcceleration;
+	ovrVector3f LinearAcceleration;
+	double TimeInSeconds;
+} ovrPoseStatef;
+
+typedef struct ovrFovPort_ { float UpTan, DownTan, LeftTan, RightTan; } ovrFovPort;
+
+typedef enum { ovrHmd_None = 0, ovrHmd_DK1 = 3, ovrHmd_DKHD = 4, ovrHmd_DK2 = 6, ovrHmd_Other } ovrHmdType;
+typedef enum { ovrEye_Left = 0, ovrEye_Right = 1, ovrEye_Count = 2 } ovrEyeType;
+
+typedef enum {
+	ovrHmdCap_Present = 0x0001,
+	ovrHmdCap_Available = 0x0002,
+	ovrHmdCap_Captured = 0x0004,
+	ovrHmdCap_ExtendDesktop = 0x0008,
+	ovrHmdCap_NoMirrorToWindow = 0x2000,
+	ovrHmdCap_DisplayOff = 0x0040,
+	ovrHmdCap_LowPersistence = 0x0080,
+	ovrHmdCap_DynamicPrediction = 0x0200,
+	ovrHmdCap_NoVSync = 0x1000,
+	ovrHmdCap_Writable_Mask = 0x33F0,
+	ovrHmdCap_Service_Mask = 0x23F0
+} ovrHmdCaps;
+
+typedef enum {
+	ovrTrackingCap_Orientation = 0x0010,
+	ovrTrackingCap_MagYawCorrection = 0x0020,
+	ovrTrackingCap_Position = 0x0040,
+	ovrTrackingCap_Idle = 0x0100
+} ovrTrackingCaps;
+
+typedef enum {
+	ovrDistortionCap_Chromatic = 0x01,
+	ovrDistortionCap_TimeWarp = 0x02,
+	ovrDistortionCap_Vignette = 0x08,
+	ovrDistortionCap_NoRestore = 0x10,
+	ovrDistortionCap_FlipInput = 0x20,
+	ovrDistortionCap_SRGB = 0x40,
+	ovrDistortionCap_Overdrive = 0x80,
+	ovrDistortionCap_ProfileNoTimewarpSpinWaits = 0x10000
+} ovrDistortionCaps;
+
+typedef enum {
+	ovrStatus_OrientationTracked = 0x0001,
+	ovrStatus_PositionTracked = 0x0002,
+	ovrStatus_CameraPoseTracked = 0x0004,
+	ovrStatus_PositionConnected = 0x0020,
+	ovrStatus_HmdConnected = 0x0080
+} ovrStatusBits;
+
+typedef struct ovrHmdDesc_ {
+	struct ovrHmdStruct* Handle;
+	ovrHmdType Type;
+	const char* ProductName;
+	const char* Manufacturer;
+	short VendorId;
+	short ProductId;
+	char SerialNumber[24];
+	short FirmwareMajor;
+	short FirmwareMinor;
+	float CameraFrustumHFovInRadians;
+	float CameraFrustumVFovInRadians;
+	float CameraFrustumNearZInMeters;
+	float CameraFrustumFarZInMeters;
+	unsigned int HmdCaps;
+	unsigned int TrackingCaps;
+	unsigned int DistortionCaps;
+	ovrFovPort DefaultEyeFov[ovrEye_Count];
+	ovrFovPort MaxEyeFov[ovrEye_Count];
+	ovrEyeType EyeRenderOrder[ovrEye_Count];
+	ovrSizei Resolution;
+	ovrVector2i WindowsPos;
+	const char* DisplayDeviceName;
+	int DisplayId;
+} ovrHmdDesc;
+
+typedef const ovrHmdDesc* ovrHmd;
+
+typedef struct ovrSensorData_ {
+	ovrVector3f Accelerometer;
+	ovrVector3f Gyro;
+	ovrVector3f Magnetometer;
+	float Temperature;
+	float TimeInSeconds;
+} ovrSensorData;
+
+typedef struct ovrTrackingState_ {
+	ovrPoseStatef HeadPose;
+	ovrPosef CameraPose;
+	ovrPosef LeveledCameraPose;
+	ovrSensorData RawSensorData;
+	unsigned int StatusFlags;
+} ovrTrackingState;
+
+typedef struct ovrFrameTiming_ {
+	float DeltaSeconds;
+	double ThisFrameSeconds;
+	double TimewarpPointSeconds;
+	double NextFrameSeconds;
+	double ScanoutMidpointSeconds;
+	double EyeScanoutSeconds[2];
+} ovrFrameTiming;
+
+typedef struct ovrEyeRenderDesc_ {
+	ovrEyeType Eye;
+	ovrFovPort Fov;
+	ovrRecti DistortedViewport;
+	ovrVector2f PixelsPerTanAngleAtCenter;
+	ovrVector3f ViewAdjust;
+} ovrEyeRenderDesc;
+
+typedef enum {
+	ovrRenderAPI_None,
+	ovrRenderAPI_OpenGL,
+	ovrRenderAPI_Android_GLES,
+	ovrRenderAPI_D3D9,
+	ovrRenderAPI_D3D10,
+	ovrRenderAPI_D3D11,
+	ovrRenderAPI_Count
+} ovrRenderAPIType;
+
+typedef struct ovrRenderAPIConfigHeader_ {
+	ovrRenderAPIType API;
+	ovrSizei RTSize;
+	int Multisample;
+} ovrRenderAPIConfigHeader;
+
+typedef struct ovrRenderAPIConfig_ {
+	ovrRenderAPIConfigHeader Header;
+	uintptr_t PlatformData[8];
+} ovrRenderAPIConfig;
+
+typedef struct ovrTextureHeader_ {
+	ovrRenderAPIType API;
+	ovrSizei TextureSize;
+	ovrRecti RenderViewport;
+} ovrTextureHeader;
+
+typedef struct ovrTexture_ {
+	ovrTextureHeader Header;
+	uintptr_t PlatformData[8];
+} ovrTexture;
+
+typedef struct ovrDistortionVertex_ {
+	ovrVector2f ScreenPosNDC;
+	float TimeWarpFactor;
+	float VignetteFactor;
+	ovrVector2f TanEyeAnglesR;
+	ovrVector2f TanEyeAnglesG;
+	ovrVector2f TanEyeAnglesB;
+} ovrDistortionVertex;
+
+typedef struct ovrDistortionMesh_ {
+	ovrDistortionVertex* pVertexData;
+	unsigned short* pIndexData;
+	unsigned int VertexCount;
+	unsigned int IndexCount;
+} ovrDistortionMesh;
+
+typedef struct ovrHSWDisplayState_ {
+	ovrBool Displayed;
+	double StartTime;
+	double DismissibleTime;
+} ovrHSWDisplayState;
+
+#endif
@@ -0,0 +1,739 @@
+/* A stand-in for libOVR 0.4.1 that the cgo layer can be tested against on
+   machines without the SDK. It implements every function the ovr package
+   loads with simple, deterministic behavior: debug devices hold still at the
+   origin, frames are 1/75th of a second apart, and the math functions use the
+   same formulas as the SDK.
+
+   A couple of properties are only known to the stub, so the tests can read
+   back what was passed in:
+
+   - "StubRenderPose" holds the render poses of the last EndFrame() as 2x7
+     floats: the orientation x, y, z, w and the position x, y, z.
+   - "StubEyeTexture" holds the eye textures of the last EndFrame() as 2x8
+     floats: the API, the texture size, the viewport and PlatformData[0].
+
+   Define OVR_STUB_VERSION to report another version, OVR_STUB_NO_HSW to
+   leave out the Health and Safety Warning functions, and
+   OVR_STUB_NO_ATTACH_TO_WINDOW to leave out ovrHmd_AttachToWindow(), like the
+   builds of libOVR for Linux and OS X. */
+
+#define _POSIX_C_SOURCE 199309L
+
+#include <stdlib.h>
+#include <string.h>
+#include <time.h>
+
+#include "OVR_CAPI_GL.h"
+
+#ifndef OVR_STUB_VERSION
+#define OVR_STUB_VERSION "libOVR:0.4.1 (stub)"
+#endif
+
+#define STUB_WRITABLE_CAPS 0x33F0
+#define STUB_HSW_DISMISS_DELAY 15.0
+#define STUB_FRAME_SECONDS (1.0 / 75.0)
+#define STUB_MESH_RESOLUTION 2
+#define STUB_MAX_PROPERTIES 32
+#define STUB_MAX_FLOATS 16
+
+// ****************************************************************************
+// ******************************** [ Devices ] *******************************
+// ****************************************************************************
+
+typedef enum { stubBool, stubInt, stubFloat, stubFloatArray, stubString } stubPropertyType;
+
+typedef struct {
+	char name[64];
+	stubPropertyType type;
+	int intValue;
+	float floatValues[STUB_MAX_FLOATS];
+	unsigned int floatCount;
+	char stringValue[128];
+} stubProperty;
+
+// The descriptor comes first, so an ovrHmd can be cast to a stubHmd.
+typedef struct {
+	ovrHmdDesc desc;
+	float pixelsPerTanAngleAtCenter;
+	char serialNumber[24];
+	char lastError[128];
+	unsigned int enabledCaps;
+	unsigned int trackingCaps;
+	double hswStartTime;
+	int hswDismissed;
+	stubProperty properties[STUB_MAX_PROPERTIES];
+	int propertyCount;
+} stubHmd;
+
+static int initialized = 0;
+
+static void setLastError(stubHmd* stub, const char* err) {
+	strncpy(stub->lastError, err, sizeof(stub->lastError) - 1);
+}
+
+static ovrFovPort mirroredFov(ovrFovPort fov) {
+	ovrFovPort mirrored = fov;
+	mirrored.LeftTan = fov.RightTan;
+	mirrored.RightTan = fov.LeftTan;
+	return mirrored;
+}
+
+ovrBool ovr_InitializeRenderingShim(void) {
+	return 1;
+}
+
+ovrBool ovr_Initialize(void) {
+	initialized = 1;
+	return 1;
+}
+
+void ovr_Shutdown(void) {
+	initialized = 0;
+}
+
+const char* ovr_GetVersionString(void) {
+	return OVR_STUB_VERSION;
+}
+
+double ovr_GetTimeInSeconds(void);
+
+ovrHmd ovrHmd_CreateDebug(ovrHmdType type);
+
+// A DK2 is attached at index 0. Like the real library, the stub lets it be
+// created more than once.
+int ovrHmd_Detect(void) {
+	return 1;
+}
+
+ovrHmd ovrHmd_Create(int index) {
+	if (index != 0) {
+		return NULL;
+	}
+
+	return ovrHmd_CreateDebug(ovrHmd_DK2);
+}
+
+ovrHmd ovrHmd_CreateDebug(ovrHmdType type) {
+	ovrFovPort fov;
+
+	stubHmd* stub = calloc(1, sizeof(stubHmd));
+	stub->desc.Handle = (struct ovrHmdStruct*)stub;
+	stub->desc.Type = type;
+	stub->desc.Manufacturer = "Oculus VR";
+	stub->desc.VendorId = 0x2833;
+	stub->desc.HmdCaps = 0x1 | 0x2 | 0x80 | 0x200 | 0x1000;
+	stub->desc.TrackingCaps = 0x10 | 0x20;
+	stub->desc.DistortionCaps = 0x01 | 0x02 | 0x08 | 0x10 | 0x20 | 0x40 | 0x10000;
+	stub->desc.EyeRenderOrder[0] = ovrEye_Left;
+	stub->desc.EyeRenderOrder[1] = ovrEye_Right;
+	stub->desc.DisplayDeviceName = "";
+	stub->desc.DisplayId = -1;
+	stub->hswStartTime = ovr_GetTimeInSeconds();
+
+	strcpy(stub->serialNumber, "STUB00000001");
+	memcpy(stub->desc.SerialNumber, stub->serialNumber, sizeof(stub->serialNumber));
+
+	switch (type) {
+	case ovrHmd_DK1:
+		stub->desc.ProductName = "Oculus Rift DK1";
+		stub->desc.ProductId = 0x0001;
+		stub->desc.Resolution.w = 1280;
+		stub->desc.Resolution.h = 800;
+		fov.UpTan = fov.DownTan = 1.3787f;
+		fov.LeftTan = 1.0540f;
+		fov.RightTan = 0.9389f;
+		stub->pixelsPerTanAngleAtCenter = 317.5f;
+		break;
+
+	case ovrHmd_DK2:
+		stub->desc.ProductName = "Oculus Rift DK2";
+		stub->desc.ProductId = 0x0021;
+		stub->desc.Resolution.w = 1920;
+		stub->desc.Resolution.h = 1080;
+		stub->desc.CameraFrustumHFovInRadians = 1.2915436f;
+		stub->desc.CameraFrustumVFovInRadians = 0.9424778f;
+		stub->desc.CameraFrustumNearZInMeters = 0.4f;
+		stub->desc.CameraFrustumFarZInMeters = 2.5f;
+		stub->desc.TrackingCaps |= 0x40;
+		stub->desc.DistortionCaps |= 0x80;
+		stub->desc.EyeRenderOrder[0] = ovrEye_Right;
+		stub->desc.EyeRenderOrder[1] = ovrEye_Left;
+		fov.UpTan = fov.DownTan = 1.3292863f;
+		fov.LeftTan = 1.0586576f;
+		fov.RightTan = 1.092368f;
+		stub->pixelsPerTanAngleAtCenter = 549.5f;
+		break;
+
+	default:
+		free(stub);
+		return NULL;
+	}
+
+	stub->desc.DefaultEyeFov[0] = stub->desc.MaxEyeFov[0] = fov;
+	stub->desc.DefaultEyeFov[1] = stub->desc.MaxEyeFov[1] = mirroredFov(fov);
+
+	return &stub->desc;
+}
+
+void ovrHmd_Destroy(ovrHmd hmd) {
+	free((stubHmd*)hmd);
+}
+
+const char* ovrHmd_GetLastError(ovrHmd hmd) {
+	return ((stubHmd*)hmd)->lastError;
+}
+
+#ifndef OVR_STUB_NO_ATTACH_TO_WINDOW
+ovrBool ovrHmd_AttachToWindow(ovrHmd hmd, void* window, const ovrRecti* destMirrorRect, const ovrRecti* sourceRenderTargetRect) {
+	(void)hmd;
+	(void)destMirrorRect;
+	(void)sourceRenderTargetRect;
+	return window != NULL;
+}
+#endif
+
+unsigned int ovrHmd_GetEnabledCaps(ovrHmd hmd) {
+	return ((stubHmd*)hmd)->enabledCaps;
+}
+
+void ovrHmd_SetEnabledCaps(ovrHmd hmd, unsigned int hmdCaps) {
+	((stubHmd*)hmd)->enabledCaps = hmdCaps & STUB_WRITABLE_CAPS;
+}
+
+// ****************************************************************************
+// ******************************** [ Tracking ] ******************************
+// ****************************************************************************
+
+ovrBool ovrHmd_ConfigureTracking(ovrHmd hmd, unsigned int supportedTrackingCaps, unsigned int requiredTrackingCaps) {
+	stubHmd* stub = (stubHmd*)hmd;
+
+	if (requiredTrackingCaps & ~(stub->desc.TrackingCaps | 0x100)) {
+		setLastError(stub, "Required tracking capabilities are not supported by this device");
+		return 0;
+	}
+
+	stub->trackingCaps = supportedTrackingCaps & (stub->desc.TrackingCaps | 0x100);
+	return 1;
+}
+
+void ovrHmd_RecenterPose(ovrHmd hmd) {
+	(void)hmd;
+}
+
+static ovrPosef identityPose(void) {
+	ovrPosef pose;
+	memset(&pose, 0, sizeof(pose));
+	pose.Orientation.w = 1;
+	return pose;
+}
+
+// The device holds still at the origin, with the camera a meter in front of
+// it, looking back.
+ovrTrackingState ovrHmd_GetTrackingState(ovrHmd hmd, double absTime) {
+	stubHmd* stub = (stubHmd*)hmd;
+
+	ovrTrackingState state;
+	memset(&state, 0, sizeof(state));
+
+	state.HeadPose.ThePose = identityPose();
+	state.HeadPose.TimeInSeconds = absTime;
+	state.RawSensorData.Accelerometer.y = 9.80665f;
+	state.RawSensorData.Magnetometer.y = -0.42f;
+	state.RawSensorData.Magnetometer.z = -0.21f;
+	state.RawSensorData.Temperature = 35;
+	state.RawSensorData.TimeInSeconds = (float)absTime;
+	state.StatusFlags = 0x80;
+
+	if (stub->trackingCaps & 0x10) {
+		state.StatusFlags |= 0x1;
+	}
+
+	if (stub->trackingCaps & 0x40) {
+		state.StatusFlags |= 0x20 | 0x2 | 0x4;
+		state.CameraPose.Orientation.y = 1;
+		state.CameraPose.Position.z = -1;
+		state.LeveledCameraPose = state.CameraPose;
+	}
+
+	return state;
+}
+
+// ****************************************************************************
+// ******************************** [ Rendering ] *****************************
+// ****************************************************************************
+
+ovrSizei ovrHmd_GetFovTextureSize(ovrHmd hmd, ovrEyeType eye, ovrFovPort fov, float pixelsPerDisplayPixel) {
+	float pixels = pixelsPerDisplayPixel * ((stubHmd*)hmd)->pixelsPerTanAngleAtCenter;
+	ovrSizei size;
+
+	(void)eye;
+	size.w = (int)(0.5f + pixels * (fov.LeftTan + fov.RightTan));
+	size.h = (int)(0.5f + pixels * (fov.UpTan + fov.DownTan));
+
+	return size;
+}
+
+static stubProperty* findProperty(stubHmd* stub, const char* propertyName, stubPropertyType type);
+
+ovrEyeRenderDesc ovrHmd_GetRenderDesc(ovrHmd hmd, ovrEyeType eyeType, ovrFovPort fov) {
+	stubHmd* stub = (stubHmd*)hmd;
+	stubProperty* ipd = findProperty(stub, "IPD", stubFloat);
+	float halfIPD = (ipd != NULL ? ipd->floatValues[0] : 0.064f) / 2;
+
+	ovrEyeRenderDesc desc;
+	memset(&desc, 0, sizeof(desc));
+
+	desc.Eye = eyeType;
+	desc.Fov = fov;
+	desc.DistortedViewport.Size.w = stub->desc.Resolution.w / 2;
+	desc.DistortedViewport.Size.h = stub->desc.Resolution.h;
+	desc.PixelsPerTanAngleAtCenter.x = stub->pixelsPerTanAngleAtCenter;
+	desc.PixelsPerTanAngleAtCenter.y = stub->pixelsPerTanAngleAtCenter;
+	desc.ViewAdjust.x = halfIPD;
+
+	if (eyeType == ovrEye_Right) {
+		desc.DistortedViewport.Pos.x = stub->desc.Resolution.w / 2;
+		desc.ViewAdjust.x = -halfIPD;
+	}
+
+	return desc;
+}
+
+static int validFov(ovrFovPort fov) {
+	return fov.LeftTan + fov.RightTan > 0 && fov.UpTan + fov.DownTan > 0;
+}
+
+ovrBool ovrHmd_ConfigureRendering(ovrHmd hmd, const ovrRenderAPIConfig* apiConfig, unsigned int distortionCaps, const ovrFovPort eyeFovIn[2], ovrEyeRenderDesc eyeRenderDescOut[2]) {
+	stubHmd* stub = (stubHmd*)hmd;
+
+	(void)distortionCaps;
+
+	if (apiConfig == NULL || apiConfig->Header.API != ovrRenderAPI_OpenGL) {
+		setLastError(stub, "Unsupported render API");
+		return 0;
+	}
+
+	if (!validFov(eyeFovIn[0]) || !validFov(eyeFovIn[1])) {
+		setLastError(stub, "Invalid eye FOV");
+		return 0;
+	}
+
+	eyeRenderDescOut[0] = ovrHmd_GetRenderDesc(hmd, ovrEye_Left, eyeFovIn[0]);
+	eyeRenderDescOut[1] = ovrHmd_GetRenderDesc(hmd, ovrEye_Right, eyeFovIn[1]);
+
+	return 1;
+}
+
+ovrFrameTiming ovrHmd_GetFrameTiming(ovrHmd hmd, unsigned int frameIndex) {
+	ovrFrameTiming timing;
+
+	(void)hmd;
+	timing.DeltaSeconds = (float)STUB_FRAME_SECONDS;
+	timing.ThisFrameSeconds = frameIndex * STUB_FRAME_SECONDS;
+	timing.NextFrameSeconds = timing.ThisFrameSeconds + STUB_FRAME_SECONDS;
+	timing.TimewarpPointSeconds = timing.NextFrameSeconds - 0.002;
+	timing.ScanoutMidpointSeconds = timing.NextFrameSeconds + STUB_FRAME_SECONDS / 2;
+	timing.EyeScanoutSeconds[0] = timing.NextFrameSeconds + STUB_FRAME_SECONDS / 4;
+	timing.EyeScanoutSeconds[1] = timing.NextFrameSeconds + STUB_FRAME_SECONDS * 3 / 4;
+
+	return timing;
+}
+
+ovrFrameTiming ovrHmd_BeginFrameTiming(ovrHmd hmd, unsigned int frameIndex) {
+	return ovrHmd_GetFrameTiming(hmd, frameIndex);
+}
+
+void ovrHmd_EndFrameTiming(ovrHmd hmd) {
+	(void)hmd;
+}
+
+void ovrHmd_ResetFrameTiming(ovrHmd hmd, unsigned int frameIndex) {
+	(void)hmd;
+	(void)frameIndex;
+}
+
+ovrFrameTiming ovrHmd_BeginFrame(ovrHmd hmd, unsigned int frameIndex) {
+	return ovrHmd_BeginFrameTiming(hmd, frameIndex);
+}
+
+static void setFloatArray(stubHmd* stub, const char* propertyName, const float values[], unsigned int arraySize);
+
+void ovrHmd_EndFrame(ovrHmd hmd, const ovrPosef renderPose[2], const ovrTexture eyeTexture[2]) {
+	stubHmd* stub = (stubHmd*)hmd;
+	float poses[14], textures[16];
+	int eye;
+
+	for (eye = 0; eye < 2; eye++) {
+		const ovrPosef* pose = &renderPose[eye];
+		const ovrTextureHeader* header = &eyeTexture[eye].Header;
+
+		float* p = &poses[eye * 7];
+		p[0] = pose->Orientation.x;
+		p[1] = pose->Orientation.y;
+		p[2] = pose->Orientation.z;
+		p[3] = pose->Orientation.w;
+		p[4] = pose->Position.x;
+		p[5] = pose->Position.y;
+		p[6] = pose->Position.z;
+
+		float* t = &textures[eye * 8];
+		t[0] = (float)header->API;
+		t[1] = (float)header->TextureSize.w;
+		t[2] = (float)header->TextureSize.h;
+		t[3] = (float)header->RenderViewport.Pos.x;
+		t[4] = (float)header->RenderViewport.Pos.y;
+		t[5] = (float)header->RenderViewport.Size.w;
+		t[6] = (float)header->RenderViewport.Size.h;
+		t[7] = (float)eyeTexture[eye].PlatformData[0];
+	}
+
+	setFloatArray(stub, "StubRenderPose", poses, 14);
+	setFloatArray(stub, "StubEyeTexture", textures, 16);
+}
+
+ovrPosef ovrHmd_GetEyePose(ovrHmd hmd, ovrEyeType eye) {
+	(void)hmd;
+	(void)eye;
+	return identityPose();
+}
+
+// ****************************************************************************
+// ****************************** [ Distortion ] ******************************
+// ****************************************************************************
+
+typedef struct {
+	ovrVector2f Scale;
+	ovrVector2f Offset;
+} scaleAndOffset2D;
+
+static scaleAndOffset2D ndcScaleAndOffsetFromFov(ovrFovPort fov) {
+	scaleAndOffset2D result;
+
+	result.Scale.x = 2.0f / (fov.LeftTan + fov.RightTan);
+	result.Offset.x = (fov.LeftTan - fov.RightTan) * result.Scale.x * 0.5f;
+	result.Scale.y = 2.0f / (fov.UpTan + fov.DownTan);
+	result.Offset.y = (fov.UpTan - fov.DownTan) * result.Scale.y * 0.5f;
+
+	return result;
+}
+
+// The mesh is a small regular grid without any distortion.
+ovrBool ovrHmd_CreateDistortionMesh(ovrHmd hmd, ovrEyeType eyeType, ovrFovPort fov, unsigned int distortionCaps, ovrDistortionMesh* meshData) {
+	stubHmd* stub = (stubHmd*)hmd;
+	const int n = STUB_MESH_RESOLUTION;
+	scaleAndOffset2D ndc = ndcScaleAndOffsetFromFov(fov);
+	int x, y;
+
+	(void)eyeType;
+	(void)distortionCaps;
+
+	if (!validFov(fov)) {
+		setLastError(stub, "Invalid eye FOV");
+		return 0;
+	}
+
+	meshData->VertexCount = (n + 1) * (n + 1);
+	meshData->IndexCount = n * n * 6;
+	meshData->pVertexData = calloc(meshData->VertexCount, sizeof(ovrDistortionVertex));
+	meshData->pIndexData = calloc(meshData->IndexCount, sizeof(unsigned short));
+
+	for (y = 0; y <= n; y++) {
+		for (x = 0; x <= n; x++) {
+			ovrDistortionVertex* vertex = &meshData->pVertexData[y * (n + 1) + x];
+
+			vertex->ScreenPosNDC.x = 2.0f * x / n - 1;
+			vertex->ScreenPosNDC.y = 2.0f * y / n - 1;
+			vertex->TimeWarpFactor = (float)x / n;
+			vertex->VignetteFactor = 1;
+			vertex->TanEyeAnglesR.x = (vertex->ScreenPosNDC.x - ndc.Offset.x) / ndc.Scale.x;
+			vertex->TanEyeAnglesR.y = (vertex->ScreenPosNDC.y - ndc.Offset.y) / ndc.Scale.y;
+			vertex->TanEyeAnglesG = vertex->TanEyeAnglesR;
+			vertex->TanEyeAnglesB = vertex->TanEyeAnglesR;
+		}
+	}
+
+	for (y = 0; y < n; y++) {
+		for (x = 0; x < n; x++) {
+			unsigned short* index = &meshData->pIndexData[(y * n + x) * 6];
+			unsigned short topLeft = (unsigned short)(y * (n + 1) + x);
+			unsigned short bottomLeft = (unsigned short)(topLeft + n + 1);
+
+			index[0] = topLeft;
+			index[1] = topLeft + 1;
+			index[2] = bottomLeft;
+			index[3] = topLeft + 1;
+			index[4] = bottomLeft + 1;
+			index[5] = bottomLeft;
+		}
+	}
+
+	return 1;
+}
+
+void ovrHmd_DestroyDistortionMesh(ovrDistortionMesh* meshData) {
+	free(meshData->pVertexData);
+	free(meshData->pIndexData);
+	memset(meshData, 0, sizeof(*meshData));
+}
+
+void ovrHmd_GetRenderScaleAndOffset(ovrFovPort fov, ovrSizei textureSize, ovrRecti renderViewport, ovrVector2f uvScaleOffsetOut[2]) {
+	scaleAndOffset2D ndc = ndcScaleAndOffsetFromFov(fov);
+	float scaleX = (float)renderViewport.Size.w / textureSize.w;
+	float scaleY = (float)renderViewport.Size.h / textureSize.h;
+	float offsetX = (float)renderViewport.Pos.x / textureSize.w;
+	float offsetY = (float)renderViewport.Pos.y / textureSize.h;
+
+	uvScaleOffsetOut[0].x = ndc.Scale.x * 0.5f * scaleX;
+	uvScaleOffsetOut[0].y = ndc.Scale.y * 0.5f * scaleY;
+	uvScaleOffsetOut[1].x = (ndc.Offset.x * 0.5f + 0.5f) * scaleX + offsetX;
+	uvScaleOffsetOut[1].y = (ndc.Offset.y * 0.5f + 0.5f) * scaleY + offsetY;
+}
+
+void ovrHmd_GetEyeTimewarpMatrices(ovrHmd hmd, ovrEyeType eye, ovrPosef renderPose, ovrMatrix4f twmOut[2]) {
+	int i;
+
+	(void)hmd;
+	(void)eye;
+	(void)renderPose;
+
+	memset(twmOut, 0, 2 * sizeof(ovrMatrix4f));
+	for (i = 0; i < 4; i++) {
+		twmOut[0].M[i][i] = 1;
+		twmOut[1].M[i][i] = 1;
+	}
+}
+
+// ****************************************************************************
+// ******************************* [ Math ] ***********************************
+// ****************************************************************************
+
+ovrMatrix4f ovrMatrix4f_Projection(ovrFovPort fov, float znear, float zfar, ovrBool rightHanded) {
+	scaleAndOffset2D ndc = ndcScaleAndOffsetFromFov(fov);
+	float handednessScale = rightHanded ? -1.0f : 1.0f;
+
+	ovrMatrix4f m;
+	memset(&m, 0, sizeof(m));
+
+	m.M[0][0] = ndc.Scale.x;
+	m.M[0][2] = handednessScale * ndc.Offset.x;
+	m.M[1][1] = ndc.Scale.y;
+	m.M[1][2] = handednessScale * -ndc.Offset.y;
+	m.M[2][2] = -handednessScale * zfar / (znear - zfar);
+	m.M[2][3] = (zfar * znear) / (znear - zfar);
+	m.M[3][2] = handednessScale;
+
+	return m;
+}
+
+ovrMatrix4f ovrMatrix4f_OrthoSubProjection(ovrMatrix4f projection, ovrVector2f orthoScale, float orthoDistance, float eyeViewAdjustX) {
+	float orthoHorizontalOffset = eyeViewAdjustX / orthoDistance;
+
+	ovrMatrix4f m;
+	memset(&m, 0, sizeof(m));
+
+	m.M[0][0] = projection.M[0][0] * orthoScale.x;
+	m.M[0][3] = -projection.M[0][2] + (orthoHorizontalOffset * projection.M[0][0]);
+	m.M[1][1] = -projection.M[1][1] * orthoScale.y;
+	m.M[1][3] = -projection.M[1][2];
+	m.M[3][3] = 1.0f;
+
+	return m;
+}
+
+double ovr_GetTimeInSeconds(void) {
+	struct timespec now;
+	clock_gettime(CLOCK_MONOTONIC, &now);
+	return now.tv_sec + now.tv_nsec / 1e9;
+}
+
+double ovr_WaitTillTime(double absTime) {
+	double initialTime = ovr_GetTimeInSeconds();
+	double newTime = initialTime;
+
+	while (newTime < absTime) {
+		newTime = ovr_GetTimeInSeconds();
+	}
+
+	return newTime - initialTime;
+}
+
+// ****************************************************************************
+// *************************** [ Latency Test ] *******************************
+// ****************************************************************************
+
+ovrBool ovrHmd_ProcessLatencyTest(ovrHmd hmd, unsigned char rgbColorOut[3]) {
+	(void)hmd;
+	(void)rgbColorOut;
+	return 0;
+}
+
+const char* ovrHmd_GetLatencyTestResult(ovrHmd hmd) {
+	(void)hmd;
+	return "";
+}
+
+// ****************************************************************************
+// ****************** [ Health and Safety Warning Display ] *******************
+// ****************************************************************************
+
+#ifndef OVR_STUB_NO_HSW
+void ovrHmd_GetHSWDisplayState(ovrHmd hmd, ovrHSWDisplayState* hasWarningState) {
+	stubHmd* stub = (stubHmd*)hmd;
+
+	hasWarningState->Displayed = !stub->hswDismissed;
+	hasWarningState->StartTime = stub->hswStartTime;
+	hasWarningState->DismissibleTime = stub->hswStartTime + STUB_HSW_DISMISS_DELAY;
+}
+
+ovrBool ovrHmd_DismissHSWDisplay(ovrHmd hmd) {
+	stubHmd* stub = (stubHmd*)hmd;
+
+	if (stub->hswDismissed || ovr_GetTimeInSeconds() < stub->hswStartTime + STUB_HSW_DISMISS_DELAY) {
+		return 0;
+	}
+
+	stub->hswDismissed = 1;
+	return 1;
+}
+#endif
+
+// ****************************************************************************
+// ***************************** [ Properties ] *******************************
+// ****************************************************************************
+
+static stubProperty* findProperty(stubHmd* stub, const char* propertyName, stubPropertyType type) {
+	int i;
+
+	for (i = 0; i < stub->propertyCount; i++) {
+		stubProperty* property = &stub->properties[i];
+		if (strcmp(property->name, propertyName) == 0) {
+			return property->type == type ? property : NULL;
+		}
+	}
+
+	return NULL;
+}
+
+// Returns the property to store a value in, which is reset to the given type.
+static stubProperty* storeProperty(stubHmd* stub, const char* propertyName, stubPropertyType type) {
+	stubProperty* property = NULL;
+	int i;
+
+	for (i = 0; i < stub->propertyCount; i++) {
+		if (strcmp(stub->properties[i].name, propertyName) == 0) {
+			property = &stub->properties[i];
+		}
+	}
+
+	if (property == NULL) {
+		if (stub->propertyCount == STUB_MAX_PROPERTIES) {
+			return NULL;
+		}
+
+		property = &stub->properties[stub->propertyCount++];
+	}
+
+	memset(property, 0, sizeof(*property));
+	strncpy(property->name, propertyName, sizeof(property->name) - 1);
+	property->type = type;
+
+	return property;
+}
+
+static void setFloatArray(stubHmd* stub, const char* propertyName, const float values[], unsigned int arraySize) {
+	stubProperty* property = storeProperty(stub, propertyName, stubFloatArray);
+	if (property == NULL) {
+		return;
+	}
+
+	if (arraySize > STUB_MAX_FLOATS) {
+		arraySize = STUB_MAX_FLOATS;
+	}
+
+	memcpy(property->floatValues, values, arraySize * sizeof(float));
+	property->floatCount = arraySize;
+}
+
+ovrBool ovrHmd_GetBool(ovrHmd hmd, const char* propertyName, ovrBool defaultVal) {
+	stubProperty* property = findProperty((stubHmd*)hmd, propertyName, stubBool);
+	return property != NULL ? (ovrBool)property->intValue : defaultVal;
+}
+
+ovrBool ovrHmd_SetBool(ovrHmd hmd, const char* propertyName, ovrBool value) {
+	stubProperty* property = storeProperty((stubHmd*)hmd, propertyName, stubBool);
+	if (property == NULL) {
+		return 0;
+	}
+
+	property->intValue = value;
+	return 1;
+}
+
+int ovrHmd_GetInt(ovrHmd hmd, const char* propertyName, int defaultVal) {
+	stubProperty* property = findProperty((stubHmd*)hmd, propertyName, stubInt);
+	return property != NULL ? property->intValue : defaultVal;
+}
+
+ovrBool ovrHmd_SetInt(ovrHmd hmd, const char* propertyName, int value) {
+	stubProperty* property = storeProperty((stubHmd*)hmd, propertyName, stubInt);
+	if (property == NULL) {
+		return 0;
+	}
+
+	property->intValue = value;
+	return 1;
+}
+
+float ovrHmd_GetFloat(ovrHmd hmd, const char* propertyName, float defaultVal) {
+	stubProperty* property = findProperty((stubHmd*)hmd, propertyName, stubFloat);
+	return property != NULL ? property->floatValues[0] : defaultVal;
+}
+
+ovrBool ovrHmd_SetFloat(ovrHmd hmd, const char* propertyName, float value) {
+	stubProperty* property = storeProperty((stubHmd*)hmd, propertyName, stubFloat);
+	if (property == NULL) {
+		return 0;
+	}
+
+	property->floatValues[0] = value;
+	property->floatCount = 1;
+	return 1;
+}
+
+unsigned int ovrHmd_GetFloatArray(ovrHmd hmd, const char* propertyName, float values[], unsigned int arraySize) {
+	stubProperty* property = findProperty((stubHmd*)hmd, propertyName, stubFloatArray);
+	if (property == NULL) {
+		return 0;
+	}
+
+	if (arraySize > property->floatCount) {
+		arraySize = property->floatCount;
+	}
+
+	memcpy(values, property->floatValues, arraySize * sizeof(float));
+	return arraySize;
+}
+
+ovrBool ovrHmd_SetFloatArray(ovrHmd hmd, const char* propertyName, float values[], unsigned int arraySize) {
+	if (arraySize > STUB_MAX_FLOATS) {
+		return 0;
+	}
+
+	setFloatArray((stubHmd*)hmd, propertyName, values, arraySize);
+	return 1;
+}
+
+const char* ovrHmd_GetString(ovrHmd hmd, const char* propertyName, const char* defaultVal) {
+	stubProperty* property = findProperty((stubHmd*)hmd, propertyName, stubString);
+	return property != NULL ? property->stringValue : defaultVal;
+}
+
+ovrBool ovrHmd_SetString(ovrHmd hmd, const char* propertyName, const char* value) {
+	stubProperty* property = storeProperty((stubHmd*)hmd, propertyName, stubString);
+	if (property == NULL) {
+		return 0;
+	}
+
+	strncpy(property->stringValue, value, sizeof(property->stringValue) - 1);
+	return 1;
+}
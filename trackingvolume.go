@@ -0,0 +1,74 @@
+package ovr
+
+// ****************************************************************************
+// **************************** [ Tracking volume ] ***************************
+// ****************************************************************************
+
+// TrackingVolumeStatus tells where the head is in the volume the camera tracks
+// its position in.
+type TrackingVolumeStatus struct {
+	// Whether the pose of the camera is known. If it isn't, the other fields
+	// are zero.
+	CameraPoseTracked bool
+
+	// Whether the head is inside the tracking volume.
+	Inside bool
+
+	// The distances in meters from the head to the planes of the tracking
+	// volume, indexed by Frustum_Left to Frustum_Far. They are positive on the
+	// inside. Left, right, bottom and top are as seen from the camera, which
+	// usually faces the user.
+	Distances [Frustum_Count]float32
+
+	// The plane the head is the closest to, or the furthest outside of.
+	ClosestPlane int
+
+	// Rises from 0 when the head comes within the warning distance of the
+	// closest plane, to 1 when it reaches the plane. Apps can use it to fade
+	// in a boundary before tracking is lost.
+	WarningLevel float32
+}
+
+// GetTrackingVolume returns the frustum the camera tracks the position of the
+// head in, in the frame of the tracking origin. It returns false if state
+// doesn't have the pose of the camera.
+func (hmd *Hmd) GetTrackingVolume(state TrackingState) (Frustum, bool) {
+	if state.StatusFlags&Status_CameraPoseTracked == 0 {
+		return Frustum{}, false
+	}
+
+	fov := FovPortFromRadians(hmd.CameraFrustumHFovInRadians, hmd.CameraFrustumVFovInRadians)
+	return FrustumFromFov(fov, state.CameraPose, hmd.CameraFrustumNearZInMeters, hmd.CameraFrustumFarZInMeters), true
+}
+
+// GetTrackingVolumeStatus returns where the head is in the tracking volume,
+// with a warning level that rises within warningDistance meters of its
+// boundary.
+func (hmd *Hmd) GetTrackingVolumeStatus(state TrackingState, warningDistance float32) TrackingVolumeStatus {
+	volume, ok := hmd.GetTrackingVolume(state)
+	if !ok {
+		return TrackingVolumeStatus{}
+	}
+
+	status := TrackingVolumeStatus{CameraPoseTracked: true}
+	position := state.HeadPose.ThePose.Position
+
+	for i, plane := range volume.Planes {
+		status.Distances[i] = plane.Distance(position)
+		if status.Distances[i] < status.Distances[status.ClosestPlane] {
+			status.ClosestPlane = i
+		}
+	}
+
+	distance := status.Distances[status.ClosestPlane]
+	status.Inside = distance >= 0
+
+	switch {
+	case distance <= 0:
+		status.WarningLevel = 1
+	case distance < warningDistance:
+		status.WarningLevel = 1 - distance/warningDistance
+	}
+
+	return status
+}
@@ -0,0 +1,62 @@
+package ovr
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetTrackingVolumeStatus(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+	state := hmd.GetTrackingState(0)
+
+	// The camera is a meter in front of the origin, looking back at it.
+	vfov := float64(hmd.CameraFrustumVFovInRadians)
+	expTop := float32(math.Sin(vfov / 2))
+
+	cases := []struct {
+		position     Vector3f
+		inside       bool
+		closestPlane int
+		distance     float32
+		warningLevel float32
+	}{
+		{Vector3f{0, 0.1, 0}, true, Frustum_Top, expTop - 0.1*float32(math.Cos(vfov/2)), 0},
+		{Vector3f{0, 0.4, 0}, true, Frustum_Top, expTop - 0.4*float32(math.Cos(vfov/2)), 1 - (expTop-0.4*float32(math.Cos(vfov/2)))/0.3},
+		{Vector3f{0, 0, -0.7}, false, Frustum_Near, -0.1, 1},
+		{Vector3f{0, 0, 1.7}, false, Frustum_Far, -0.2, 1},
+	}
+
+	for _, c := range cases {
+		state.HeadPose.ThePose.Position = c.position
+		status := hmd.GetTrackingVolumeStatus(state, 0.3)
+
+		if !status.CameraPoseTracked || status.Inside != c.inside || status.ClosestPlane != c.closestPlane {
+			t.Errorf("Expected the head at %v to be inside (%t) and closest to plane %d, instead of %v", c.position, c.inside, c.closestPlane, status)
+		}
+
+		if distance := status.Distances[c.closestPlane]; !approxFloat(c.distance, distance, 1e-5) {
+			t.Errorf("Expected the head at %v to be %f meters from plane %d, instead of %f", c.position, c.distance, c.closestPlane, distance)
+		}
+
+		if !approxFloat(c.warningLevel, status.WarningLevel, 1e-4) {
+			t.Errorf("Expected a warning level of %f at %v, instead of %f", c.warningLevel, c.position, status.WarningLevel)
+		}
+	}
+
+	// The volume follows the camera when the pose is recentered.
+	hmd.RecenterPose()
+	if status := hmd.GetTrackingVolumeStatus(hmd.GetTrackingState(0), 0.3); !status.Inside || !approxFloat(0.6, status.Distances[Frustum_Near], 1e-5) {
+		t.Errorf("Expected the head to be 0.6 meters behind the near plane after recentering, instead of %v", status)
+	}
+
+	dk1 := HmdCreateSimulated(Hmd_DK1)
+	defer dk1.Destroy()
+
+	dk1.ConfigureTracking(TrackingCap_Orientation, 0)
+	if status := dk1.GetTrackingVolumeStatus(dk1.GetTrackingState(0), 0.3); status != (TrackingVolumeStatus{}) {
+		t.Errorf("Expected no tracking volume without a camera, instead of %v", status)
+	}
+}
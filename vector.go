@@ -0,0 +1,45 @@
+package ovr
+
+import "math"
+
+// ****************************************************************************
+// ****************************** [ Vector math ] *****************************
+// ****************************************************************************
+
+func vectorAdd(a, b Vector3f) Vector3f {
+	return Vector3f{a.X + b.X, a.Y + b.Y, a.Z + b.Z}
+}
+
+func vectorSub(a, b Vector3f) Vector3f {
+	return Vector3f{a.X - b.X, a.Y - b.Y, a.Z - b.Z}
+}
+
+func vectorScale(v Vector3f, s float32) Vector3f {
+	return Vector3f{v.X * s, v.Y * s, v.Z * s}
+}
+
+func vectorDot(a, b Vector3f) float32 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func vectorCross(a, b Vector3f) Vector3f {
+	return Vector3f{a.Y*b.Z - a.Z*b.Y, a.Z*b.X - a.X*b.Z, a.X*b.Y - a.Y*b.X}
+}
+
+func vectorLength(v Vector3f) float32 {
+	return float32(math.Sqrt(float64(vectorDot(v, v))))
+}
+
+// Returns v scaled to a length of one, or v itself if it has a length of zero.
+func vectorNormalize(v Vector3f) Vector3f {
+	length := vectorLength(v)
+	if length == 0 {
+		return v
+	}
+
+	return vectorScale(v, 1/length)
+}
+
+func vectorLerp(a, b Vector3f, f float32) Vector3f {
+	return Vector3f{a.X + (b.X-a.X)*f, a.Y + (b.Y-a.Y)*f, a.Z + (b.Z-a.Z)*f}
+}
@@ -0,0 +1,142 @@
+package ovr
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The status bits a StatusWatcher reports changes of.
+var watchedStatus = []uint{
+	Status_HmdConnected,
+	Status_PositionConnected,
+	Status_PositionTracked,
+	Status_OrientationTracked,
+	Status_CameraPoseTracked,
+}
+
+// Returns the name of a status bit, as used in motion scripts.
+func statusName(status uint) string {
+	for name, bit := range statusNames {
+		if bit == status {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("0x%x", status)
+}
+
+// A StatusEvent reports that one of the status bits of the tracking state was
+// set or cleared.
+type StatusEvent struct {
+	// The bit that changed, one of the Status_* constants.
+	Status uint
+
+	// When the change was noticed, on the clock of GetTimeInSeconds().
+	TimeInSeconds float64
+
+	Previous bool
+	Current  bool
+
+	// All the status bits of the tracking state before and after the change.
+	PreviousFlags uint
+	CurrentFlags  uint
+}
+
+func (event StatusEvent) String() string {
+	return fmt.Sprintf("%s: %t -> %t at %.3fs", statusName(event.Status), event.Previous, event.Current, event.TimeInSeconds)
+}
+
+// A StatusWatcher polls the tracking state of an Hmd, and sends a StatusEvent
+// for every change in the Status_HmdConnected, Status_PositionConnected,
+// Status_PositionTracked, Status_OrientationTracked and
+// Status_CameraPoseTracked bits.
+type StatusWatcher struct {
+	events   chan StatusEvent
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// WatchStatus starts polling the tracking state of hmd every interval. The
+// first poll only records the initial state, so events are only sent for the
+// changes after it. When several bits change at once, their events are sent
+// in the order of the list above.
+func WatchStatus(hmd *Hmd, interval time.Duration) *StatusWatcher {
+	watcher := newStatusWatcher()
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		watcher.run(hmd, ticker.C, GetTimeInSeconds)
+	}()
+
+	return watcher
+}
+
+func newStatusWatcher() *StatusWatcher {
+	return &StatusWatcher{
+		events: make(chan StatusEvent, 16),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Events returns the channel the events are sent on. It is closed once the
+// watcher is stopped. Polling waits while the channel is full, so it should
+// be drained regularly.
+func (watcher *StatusWatcher) Events() <-chan StatusEvent {
+	return watcher.events
+}
+
+// Stop stops polling and closes the event channel. Events that were already
+// sent can still be received. It can be called more than once.
+func (watcher *StatusWatcher) Stop() {
+	watcher.stopOnce.Do(func() {
+		close(watcher.stop)
+	})
+	<-watcher.done
+}
+
+// Polls the tracking state of hmd at every tick, at the time given by clock.
+func (watcher *StatusWatcher) run(hmd *Hmd, ticks <-chan time.Time, clock func() float64) {
+	defer close(watcher.done)
+	defer close(watcher.events)
+
+	now := clock()
+	flags := hmd.GetTrackingState(now).StatusFlags
+
+	for {
+		select {
+		case <-watcher.stop:
+			return
+		case <-ticks:
+		}
+
+		now = clock()
+		newFlags := hmd.GetTrackingState(now).StatusFlags
+
+		for _, status := range watchedStatus {
+			if (flags^newFlags)&status == 0 {
+				continue
+			}
+
+			event := StatusEvent{
+				Status:        status,
+				TimeInSeconds: now,
+				Previous:      flags&status != 0,
+				Current:       newFlags&status != 0,
+				PreviousFlags: flags,
+				CurrentFlags:  newFlags,
+			}
+
+			select {
+			case watcher.events <- event:
+			case <-watcher.stop:
+				return
+			}
+		}
+
+		flags = newFlags
+	}
+}
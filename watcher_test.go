@@ -0,0 +1,79 @@
+package ovr
+
+import (
+	"testing"
+	"time"
+)
+
+// The watcher is driven by hand, with a tick for every poll and a clock that
+// steps through the times of the polls, so the test doesn't depend on timing.
+func TestWatchStatus(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	hmd.ConfigureTracking(TrackingCap_Orientation|TrackingCap_Position, 0)
+	hmd.Simulator().SetMotionScript(&MotionScript{
+		Duration: 10,
+		TrackingLoss: []TrackingLoss{
+			{Start: 0.05, End: 0.15, Lost: Status_PositionTracked | Status_CameraPoseTracked},
+		},
+	}, 0)
+
+	// The first time is the initial state. The last poll changes nothing, and
+	// only makes sure the one before it has sent its events.
+	times := []float64{0, 0.1, 0.12, 0.2, 0.25}
+	clock := func() float64 {
+		now := times[0]
+		times = times[1:]
+		return now
+	}
+
+	ticks := make(chan time.Time)
+	watcher := newStatusWatcher()
+	go watcher.run(hmd, ticks, clock)
+
+	for i := 1; i < 5; i++ {
+		ticks <- time.Time{}
+	}
+
+	watcher.Stop()
+	watcher.Stop()
+
+	var tracked uint = Status_HmdConnected | Status_OrientationTracked | Status_PositionConnected | Status_PositionTracked | Status_CameraPoseTracked
+	lost := tracked &^ (Status_PositionTracked | Status_CameraPoseTracked)
+
+	expEvents := []StatusEvent{
+		{Status: Status_PositionTracked, TimeInSeconds: 0.1, Previous: true, Current: false, PreviousFlags: tracked, CurrentFlags: lost},
+		{Status: Status_CameraPoseTracked, TimeInSeconds: 0.1, Previous: true, Current: false, PreviousFlags: tracked, CurrentFlags: lost},
+		{Status: Status_PositionTracked, TimeInSeconds: 0.2, Previous: false, Current: true, PreviousFlags: lost, CurrentFlags: tracked},
+		{Status: Status_CameraPoseTracked, TimeInSeconds: 0.2, Previous: false, Current: true, PreviousFlags: lost, CurrentFlags: tracked},
+	}
+
+	events := []StatusEvent{}
+	for event := range watcher.Events() {
+		events = append(events, event)
+	}
+
+	if len(events) != len(expEvents) {
+		t.Fatalf("Expected %d events, instead of %v", len(expEvents), events)
+	}
+
+	for i, expEvent := range expEvents {
+		if events[i] != expEvent {
+			t.Errorf("Expected event '%s' (%+v), instead of '%s' (%+v)", expEvent, expEvent, events[i], events[i])
+		}
+	}
+}
+
+func TestWatchStatusStop(t *testing.T) {
+	hmd := HmdCreateSimulated(Hmd_DK2)
+	defer hmd.Destroy()
+
+	watcher := WatchStatus(hmd, time.Millisecond)
+	watcher.Stop()
+	watcher.Stop()
+
+	if _, ok := <-watcher.Events(); ok {
+		t.Error("Expected no more events once the watcher is stopped")
+	}
+}